@@ -0,0 +1,85 @@
+package datatables
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// aclContext returns the context passed to Options.ColumnACL: the request's,
+// or an empty context when rows are produced without a request (OfParams),
+// so accessors such as c.GetString work and request-based checks deny.
+func aclContext(c *gin.Context) *gin.Context {
+	if c == nil {
+		return &gin.Context{}
+	}
+	return c
+}
+
+// aclColumns removes the searchable and orderable columns hidden by
+// Options.ColumnACL, so the values of a hidden column cannot be probed
+// through the global search, per-column searches, or ordering.
+//
+// A column is hidden when the ACL rejects any name it appears under in the
+// output: its frontend name, its database column (with and without the
+// table prefix), or their aliases. The orderable map is copied, never modified.
+func aclColumns(c *gin.Context, searchable []string, orderable map[string]string, opts Options) ([]string, map[string]string) {
+	visible := make(map[string]bool)
+	allowed := func(names ...string) bool {
+		for _, name := range aclNames(names, opts.ColumnAliases) {
+			ok, seen := visible[name]
+			if !seen {
+				ok = opts.ColumnACL(c, name)
+				visible[name] = ok
+			}
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	var kept []string
+	for _, col := range searchable {
+		names := []string{col}
+		for _, name := range sortedKeys(opts.SearchableMap) {
+			if opts.SearchableMap[name] == col {
+				names = append(names, name)
+			}
+		}
+		if allowed(names...) {
+			kept = append(kept, col)
+		}
+	}
+
+	var keptOrderable map[string]string
+	if orderable != nil {
+		keptOrderable = make(map[string]string, len(orderable))
+		for _, key := range sortedKeys(orderable) {
+			if allowed(key, orderable[key]) {
+				keptOrderable[key] = orderable[key]
+			}
+		}
+	}
+
+	return kept, keptOrderable
+}
+
+// aclNames expands column names into every name the ACL may see them under:
+// the name, its column part without the table prefix, and their aliases.
+func aclNames(names []string, aliases map[string]string) []string {
+	var out []string
+	add := func(name string) {
+		if name != "" && !containsString(out, name) {
+			out = append(out, name)
+		}
+	}
+
+	for _, name := range names {
+		column := name[strings.LastIndex(name, ".")+1:]
+		for _, n := range []string{name, column, aliases[name], aliases[column]} {
+			add(n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,216 @@
+package datatables
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// tagName is the struct tag used to annotate DataTables column behavior.
+//
+// Supported values (comma separated):
+//   - searchable: The column participates in the global search
+//   - orderable: The column can be ordered by the client
+//   - "-": The column is excluded from the metadata
+//
+// Example:
+//   type User struct {
+//       Name  string `json:"name" datatables:"searchable,orderable"`
+//       Email string `json:"email" datatables:"searchable"`
+//   }
+const tagName = "datatables"
+
+// ColumnMetadata describes a single output column for frontend auto-configuration.
+type ColumnMetadata struct {
+	Name       string `json:"name"`       // Database column name (empty for computed columns)
+	JSONKey    string `json:"data"`       // Key of the column in the DataTables data rows
+	Type       string `json:"type"`       // Value type: string, integer, number, boolean, datetime, or object
+	Searchable bool   `json:"searchable"` // Whether the column participates in the global search
+	Orderable  bool   `json:"orderable"`  // Whether the column can be ordered
+	Computed   bool   `json:"computed"`   // Whether the column is computed in Go (Options.Add)
+}
+
+// ColumnMeta reflects a model and returns the metadata of its output columns,
+// so the frontend can fetch it once to build its DataTables column definitions.
+//
+// Searchable and Orderable flags are read from the `datatables` struct tag.
+// JSON keys follow the same rules as the converter, and database column names
+// follow GORM's default naming strategy and `gorm:"column:..."` tags.
+//
+// When Options are provided, their Add columns are appended as computed columns
+// (never searchable or orderable) and their removed columns are omitted.
+//
+// Example:
+//   meta, err := datatables.ColumnMeta(&User{}, opts)
+//   c.JSON(200, meta)
+func ColumnMeta(model interface{}, opts ...Options) ([]ColumnMetadata, error) {
+	modelSchema, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, o := range opts {
+		removed = append(removed, o.RemoveColumns...)
+	}
+	removed = append(removed, globalRemoveColumns()...)
+
+	modelType := modelSchema.ModelType
+	columns := make([]ColumnMetadata, 0, modelType.NumField())
+
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := getFieldName(field)
+		tag := field.Tag.Get(tagName)
+		if key == "" || tag == "-" || containsString(removed, key) {
+			continue
+		}
+
+		meta := ColumnMetadata{
+			JSONKey:    key,
+			Type:       columnType(field.Type),
+			Searchable: hasTagOption(tag, "searchable"),
+			Orderable:  hasTagOption(tag, "orderable"),
+		}
+		if schemaField := modelSchema.LookUpField(field.Name); schemaField != nil {
+			meta.Name = schemaField.DBName
+		}
+
+		columns = append(columns, meta)
+	}
+
+	// Computed columns are never searchable or orderable at the SQL level
+	for _, o := range opts {
+		for _, col := range sortedAddColumns(o.AddColumns) {
+			if containsString(removed, col) {
+				continue
+			}
+			columns = append(columns, ColumnMetadata{JSONKey: col, Type: "object", Computed: true})
+		}
+	}
+
+	return columns, nil
+}
+
+// OrderableFromModel reflects a model and returns the orderable map of the
+// fields tagged `datatables:"orderable"`, keeping it in sync with the model.
+// Keys are the frontend names (the JSON keys, following the same rules as the
+// converter) and values the database columns (following GORM's default naming
+// strategy and `gorm:"column:..."` tags). Fields without a database column are skipped.
+//
+// Example:
+//   orderable, err := datatables.OrderableFromModel(&User{})
+//   // map[string]string{"name": "name", "createdAt": "created_at"}
+func OrderableFromModel(model interface{}) (map[string]string, error) {
+	modelSchema, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	modelType := modelSchema.ModelType
+	orderable := make(map[string]string)
+
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if !field.IsExported() || !hasTagOption(field.Tag.Get(tagName), "orderable") {
+			continue
+		}
+
+		key := getFieldName(field)
+		schemaField := modelSchema.LookUpField(field.Name)
+		if key == "" || schemaField == nil || schemaField.DBName == "" {
+			continue
+		}
+
+		orderable[key] = schemaField.DBName
+	}
+
+	return orderable, nil
+}
+
+// hasTagOption reports whether a comma separated tag value contains the option.
+func hasTagOption(tag, option string) bool {
+	for _, opt := range strings.Split(tag, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// columnType maps a Go type to a simple type name for the frontend.
+func columnType(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return "datetime"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return "object"
+}
+
+// sortedAddColumns returns the names of the Add columns in sorted order.
+func sortedAddColumns(columns map[string]func(row map[string]interface{}) interface{}) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stringColumnsFromModel resolves the database column names of all
+// string-typed fields on a GORM model.
+//
+// The model's schema is parsed using the query's naming strategy, so the
+// returned names match the columns GORM uses (e.g. "FirstName" -> "first_name").
+// Only string and *string fields participate, avoiding type issues when the
+// columns are used in LOWER(...) LIKE searches.
+//
+// Returns an error if the model cannot be parsed.
+func stringColumnsFromModel(db *gorm.DB, model interface{}) ([]string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(stmt.Schema.Fields))
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		fieldType := field.FieldType
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.String {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,146 @@
+package datatables
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type TestCustomer struct {
+	ID        uint
+	FirstName string
+	Nickname  *string
+	Email     string `gorm:"column:email_address"`
+	Age       int
+	CreatedAt time.Time
+	Ignored   string `gorm:"-"`
+}
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	return db
+}
+
+func TestStringColumnsFromModel(t *testing.T) {
+	db := newDryRunDB(t)
+
+	columns, err := stringColumnsFromModel(db, &TestCustomer{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"first_name", "nickname", "email_address"}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected %v, got %v", expected, columns)
+	}
+}
+
+func TestStringColumnsFromModelSlice(t *testing.T) {
+	db := newDryRunDB(t)
+
+	var customers []TestCustomer
+	columns, err := stringColumnsFromModel(db, &customers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(columns) != 3 {
+		t.Errorf("Expected 3 string columns, got %v", columns)
+	}
+}
+
+type TestArticle struct {
+	ID        uint      `json:"id" datatables:"orderable"`
+	Title     string    `json:"title" datatables:"searchable,orderable"`
+	Body      string    `json:"body" datatables:"searchable"`
+	Rating    float64   `json:"rating"`
+	Published bool      `json:"published"`
+	AuthorID  *int      `json:"author_id" gorm:"column:writer_id"`
+	CreatedAt time.Time `json:"created_at" datatables:"orderable"`
+	Secret    string    `json:"-"`
+	Internal  string    `json:"internal" datatables:"-"`
+}
+
+func TestColumnMeta(t *testing.T) {
+	t.Run("Reflects model fields and tags", func(t *testing.T) {
+		meta, err := ColumnMeta(&TestArticle{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []ColumnMetadata{
+			{Name: "id", JSONKey: "id", Type: "integer", Orderable: true},
+			{Name: "title", JSONKey: "title", Type: "string", Searchable: true, Orderable: true},
+			{Name: "body", JSONKey: "body", Type: "string", Searchable: true},
+			{Name: "rating", JSONKey: "rating", Type: "number"},
+			{Name: "published", JSONKey: "published", Type: "boolean"},
+			{Name: "writer_id", JSONKey: "author_id", Type: "integer"},
+			{Name: "created_at", JSONKey: "created_at", Type: "datetime", Orderable: true},
+		}
+		if !reflect.DeepEqual(meta, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, meta)
+		}
+	})
+
+	t.Run("Includes computed and omits removed columns", func(t *testing.T) {
+		opts := NewOptions().
+			Add("action", func(row map[string]interface{}) interface{} { return "" }).
+			Remove("body", "rating")
+
+		meta, err := ColumnMeta(&TestArticle{}, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		last := meta[len(meta)-1]
+		if last.JSONKey != "action" || !last.Computed || last.Searchable || last.Orderable {
+			t.Errorf("Expected non-searchable, non-orderable computed column, got %+v", last)
+		}
+		for _, m := range meta {
+			if m.JSONKey == "body" || m.JSONKey == "rating" {
+				t.Errorf("Removed column %q should be omitted", m.JSONKey)
+			}
+		}
+	})
+}
+
+type TestRankedArticle struct {
+	ID        uint      `json:"id" datatables:"orderable"`
+	Title     string    `json:"headline" datatables:"searchable,orderable"`
+	Score     int       `json:"score" gorm:"column:rank_score" datatables:"orderable"`
+	Author    string    `datatables:"orderable"`
+	Body      string    `json:"body" datatables:"searchable"`
+	Secret    string    `json:"-" datatables:"orderable"`
+	Virtual   string    `json:"virtual" gorm:"-" datatables:"orderable"`
+	CreatedAt time.Time `json:"createdAt" datatables:"orderable"`
+}
+
+func TestOrderableFromModel(t *testing.T) {
+	orderable, err := OrderableFromModel(&TestRankedArticle{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"id":        "id",
+		"headline":  "title",
+		"score":     "rank_score",
+		"Author":    "author",
+		"createdAt": "created_at",
+	}
+	if !reflect.DeepEqual(orderable, expected) {
+		t.Errorf("Expected %v, got %v", expected, orderable)
+	}
+
+	if _, err := OrderableFromModel("not a model"); err == nil {
+		t.Error("Expected an error for an invalid model")
+	}
+}
@@ -0,0 +1,54 @@
+package datatables
+
+import (
+	"compress/gzip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeGzipJSON writes v as a gzip-compressed JSON response when compression
+// is enabled with SetGzipThreshold, the client accepts gzip, and the encoded
+// payload reaches the threshold. Returns false without writing anything
+// otherwise, so the caller can send plain JSON.
+func writeGzipJSON(c *gin.Context, status int, v interface{}) bool {
+	threshold := gzipThreshold()
+	if threshold <= 0 || !acceptsGzip(c) {
+		return false
+	}
+
+	body, err := marshalJSON(v, !usePureJSON(c))
+	if err != nil || len(body) < threshold {
+		return false
+	}
+
+	c.Header("Content-Encoding", "gzip")
+	c.Header("Vary", "Accept-Encoding")
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(status)
+
+	gz := gzip.NewWriter(c.Writer)
+	defer gz.Close()
+	_, _ = gz.Write(body)
+	return true
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding header allows gzip.
+func acceptsGzip(c *gin.Context) bool {
+	if c.Request == nil {
+		return false
+	}
+
+	for _, part := range strings.Split(c.GetHeader("Accept-Encoding"), ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(fields[0]), "gzip") {
+			continue
+		}
+		// "gzip;q=0" explicitly refuses gzip
+		if len(fields) > 1 && strings.ReplaceAll(strings.TrimSpace(fields[1]), " ", "") == "q=0" {
+			return false
+		}
+		return true
+	}
+	return false
+}
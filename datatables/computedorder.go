@@ -0,0 +1,108 @@
+package datatables
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+)
+
+// computedOrderColumn returns the Add column the request orders by when
+// ordering by computed columns is enabled (see WithComputedOrder), or an
+// empty string. Orderable columns are ordered in SQL as usual.
+func computedOrderColumn(params dto.Params, orderable map[string]string, opts Options) string {
+	if !opts.ComputedOrder || params.Order == "" || opts.OrderingDisabled {
+		return ""
+	}
+	if _, ok := orderable[params.Order]; ok {
+		return ""
+	}
+
+	_, added := opts.AddColumns[params.Order]
+	_, addedWithValues := opts.AddValueColumns[params.Order]
+	if !added && !addedWithValues {
+		return ""
+	}
+	return params.Order
+}
+
+// sortByComputed sorts rows by the value the Add callback of column computes
+// for them. The sort is stable, so rows with equal values keep the database
+// order. Numbers and times compare by value, other values as text.
+func sortByComputed(rows []map[string]interface{}, column, dir string, opts Options) {
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		if fn, ok := opts.AddColumns[column]; ok {
+			values[i] = fn(copyRow(row))
+		} else {
+			values[i] = opts.AddValueColumns[column](copyRow(row), opts.ContextValues)
+		}
+	}
+
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+
+	desc := normalizeDir(dir) == "desc"
+	sort.SliceStable(order, func(a, b int) bool {
+		cmp := compareValues(values[order[a]], values[order[b]])
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
+	sorted := make([]map[string]interface{}, len(rows))
+	for i, index := range order {
+		sorted[i] = rows[index]
+	}
+	copy(rows, sorted)
+}
+
+// compareValues compares two computed values, returning -1, 0, or 1.
+// Nil sorts first.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if isNumeric(a) && isNumeric(b) {
+		x, y := numericFloat(a), numericFloat(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y)
+		}
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// numericFloat converts an integer or floating point value to a float64.
+func numericFloat(value interface{}) float64 {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
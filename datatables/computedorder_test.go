@@ -0,0 +1,114 @@
+package datatables
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOfReturnComputedOrder(t *testing.T) {
+	result := userResult(
+		TestUser{ID: 1, Name: "Carol", Email: "carol@example.com"},
+		TestUser{ID: 2, Name: "alice", Email: "alice@example.com"},
+		TestUser{ID: 3, Name: "Bob", Email: "bob@example.com"},
+	)
+	displayName := func(row map[string]interface{}) interface{} {
+		return strings.ToUpper(row["name"].(string))
+	}
+
+	names := func(data interface{}) []string {
+		var names []string
+		for _, row := range data.([]map[string]interface{}) {
+			names = append(names, row["display_name"].(string))
+		}
+		return names
+	}
+
+	t.Run("Page is sorted by the computed value", func(t *testing.T) {
+		fake := &fakeDB{count: 3, result: result}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("start=0&length=3&order[0][column]=display_name&order[0][dir]=desc")
+
+		var users []TestUser
+		opts := NewOptions().Add("display_name", displayName).WithComputedOrder(false)
+		res, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, map[string]string{"name": "name"}, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := strings.Join(names(res.Data), ","); got != "CAROL,BOB,ALICE" {
+			t.Errorf("Expected CAROL,BOB,ALICE, got %s", got)
+		}
+		rows := res.Data.([]map[string]interface{})
+		if rows[0]["DT_RowIndex"] != 1 || rows[2]["DT_RowIndex"] != 3 {
+			t.Errorf("Expected the index to follow the sorted order, got %v", rows)
+		}
+		if last := fake.LastSelect(); !strings.Contains(last.SQL, "LIMIT") || strings.Contains(last.SQL, "ORDER BY") {
+			t.Errorf("Expected a paginated query without SQL ordering, got %s", last.SQL)
+		}
+	})
+
+	t.Run("Full set is sorted before pagination", func(t *testing.T) {
+		fake := &fakeDB{count: 3, result: result}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("start=1&length=2&order[0][column]=display_name&order[0][dir]=asc")
+
+		var users []TestUser
+		opts := NewOptions().Add("display_name", displayName).WithComputedOrder(true)
+		res, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := strings.Join(names(res.Data), ","); got != "BOB,CAROL" {
+			t.Errorf("Expected the second page BOB,CAROL, got %s", got)
+		}
+		if res.RecordsTotal != 3 || res.RecordsFiltered != 3 {
+			t.Errorf("Expected counts 3/3, got %d/%d", res.RecordsTotal, res.RecordsFiltered)
+		}
+		if last := fake.LastSelect(); strings.Contains(last.SQL, "LIMIT") {
+			t.Errorf("Expected the whole set to be fetched, got %s", last.SQL)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		fake := &fakeDB{count: 3, result: result}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("order[0][column]=display_name&order[0][dir]=asc")
+
+		var users []TestUser
+		res, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().Add("display_name", displayName))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := strings.Join(names(res.Data), ","); got != "CAROL,ALICE,BOB" {
+			t.Errorf("Expected the database order, got %s", got)
+		}
+	})
+}
+
+func TestCompareValues(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		a, b     interface{}
+		expected int
+	}{
+		{"Numbers by value", 9, int64(10), -1},
+		{"Mixed numeric kinds", 2.5, uint8(2), 1},
+		{"Times by value", early.Add(time.Hour), early, 1},
+		{"Strings", "apple", "banana", -1},
+		{"Equal", "x", "x", 0},
+		{"Nil sorts first", nil, 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareValues(tt.a, tt.b); got != tt.expected {
+				t.Errorf("compareValues(%v, %v) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
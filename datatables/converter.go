@@ -1,7 +1,12 @@
 package datatables
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -10,7 +15,8 @@ import (
 //
 // Supported struct tag formats:
 //   - `json:"field_name"`: Uses "field_name" as the map key
-//   - `json:"field_name,omitempty"`: Uses "field_name" (options are ignored)
+//   - `json:"field_name,omitempty"`: Uses "field_name" (omitempty is ignored; see Options.WithOmitEmpty)
+//   - `json:"field_name,string"`: Stores the value in its JSON string form
 //   - `json:"-"`: Field is excluded from output
 //   - No tag: Uses the field name as-is
 //
@@ -52,6 +58,12 @@ func structToMapSlice(data interface{}) []map[string]interface{} {
 			item = item.Elem()
 		}
 
+		// Rows scanned into maps keep their keys; values are normalized
+		if item.Kind() == reflect.Map {
+			result = append(result, normalizeMapRow(item))
+			continue
+		}
+
 		// Convert struct to map
 		m := structToMap(item)
 		result = append(result, m)
@@ -83,6 +95,12 @@ func structToMap(v reflect.Value) map[string]interface{} {
 			continue
 		}
 
+		// Honor the ",string" option like encoding/json does
+		if hasJSONOption(field, "string") {
+			m[col] = quotedValue(fieldValue)
+			continue
+		}
+
 		// Add field to map
 		m[col] = fieldValue.Interface()
 	}
@@ -90,6 +108,89 @@ func structToMap(v reflect.Value) map[string]interface{} {
 	return m
 }
 
+// driverNumberPattern matches plain decimal numbers as drivers return them in
+// text form (e.g. "42", "-3.50"), excluding leading zeros that are likely codes
+var driverNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// normalizeMapRow copies a map row (e.g. from Find into []map[string]interface{})
+// and normalizes driver-dependent value types, so the output is the same
+// regardless of the database driver:
+//   - []byte holding a plain decimal number becomes int64 or float64, other []byte
+//     become strings (values with leading zeros, such as "007", stay strings)
+//   - signed and unsigned integers become int64 (uint64 values above the int64 range are kept)
+//   - float32 becomes float64
+func normalizeMapRow(v reflect.Value) map[string]interface{} {
+	m := make(map[string]interface{}, v.Len())
+
+	iter := v.MapRange()
+	for iter.Next() {
+		m[fmt.Sprint(iter.Key().Interface())] = normalizeDriverValue(iter.Value().Interface())
+	}
+
+	return m
+}
+
+// normalizeDriverValue normalizes a single scanned value for normalizeMapRow.
+func normalizeDriverValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case []byte:
+		s := string(val)
+		if !driverNumberPattern.MatchString(s) {
+			return s
+		}
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+		return s
+	case int:
+		return int64(val)
+	case int8:
+		return int64(val)
+	case int16:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case uint:
+		return normalizeUint(uint64(val))
+	case uint8:
+		return int64(val)
+	case uint16:
+		return int64(val)
+	case uint32:
+		return int64(val)
+	case uint64:
+		return normalizeUint(val)
+	case float32:
+		return float64(val)
+	}
+	return value
+}
+
+// normalizeNumbers converts the integer and float32 values of struct rows
+// like normalizeDriverValue (see Options.WithNormalizeNumbers). Byte slices
+// and named numeric types, which may have their own JSON encoding, are kept.
+func normalizeNumbers(rows []map[string]interface{}) {
+	for _, row := range rows {
+		for key, value := range row {
+			if _, ok := value.([]byte); ok {
+				continue
+			}
+			row[key] = normalizeDriverValue(value)
+		}
+	}
+}
+
+// normalizeUint converts an unsigned integer to int64 when it fits.
+func normalizeUint(val uint64) interface{} {
+	if val > math.MaxInt64 {
+		return val
+	}
+	return int64(val)
+}
+
 // getFieldName extracts the field name from the JSON struct tag.
 // Returns an empty string if the field should be excluded (json:"-").
 func getFieldName(field reflect.StructField) string {
@@ -110,3 +211,122 @@ func getFieldName(field reflect.StructField) string {
 	parts := strings.Split(jsonTag, ",")
 	return parts[0]
 }
+
+// hasJSONOption reports whether the field's JSON tag contains the given option
+// (e.g. "string" in `json:"id,string"`).
+func hasJSONOption(field reflect.StructField, option string) bool {
+	parts := strings.Split(field.Tag.Get("json"), ",")
+	for _, opt := range parts[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedValue converts a field tagged with ",string" into its string form,
+// matching encoding/json semantics: the option applies to string, floating point,
+// integer, and boolean fields (or pointers to them). Nil pointers stay nil and
+// other kinds are returned unchanged.
+func quotedValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		// encoding/json writes the JSON encoding of the value inside a string,
+		// so strings end up double-quoted (e.g. "abc" -> "\"abc\"")
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return v.Interface()
+		}
+		return string(b)
+	}
+
+	return v.Interface()
+}
+
+// flattenCollections replaces slice and map values in each row with
+// display-friendly strings. Rows are modified in place.
+//
+// Conversion rules:
+//   - Slices and arrays: elements joined with sep (e.g. ["a","b"] -> "a, b")
+//   - Maps: JSON-encoded (e.g. {"k":"v"} -> `{"k":"v"}`)
+//   - Byte slices and scalar values: left untouched
+func flattenCollections(rows []map[string]interface{}, sep string) {
+	for _, row := range rows {
+		for k, v := range row {
+			row[k] = flattenValue(v, sep)
+		}
+	}
+}
+
+// flattenValue converts a single slice or map value into a string.
+func flattenValue(value interface{}, sep string) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		// Keep []byte as-is
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return value
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return ""
+		}
+
+		parts := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			parts = append(parts, stringifyElement(v.Index(i).Interface()))
+		}
+		return strings.Join(parts, sep)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return ""
+		}
+		return encodeJSONString(value)
+	}
+
+	return value
+}
+
+// stringifyElement converts a collection element into its string form.
+// Nested collections and structs are JSON-encoded; scalars use fmt formatting.
+func stringifyElement(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return encodeJSONString(value)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// encodeJSONString marshals a value to JSON, falling back to fmt formatting
+// if the value cannot be encoded.
+func encodeJSONString(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
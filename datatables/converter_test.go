@@ -1,6 +1,10 @@
 package datatables
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -153,3 +157,234 @@ func TestGetFieldName(t *testing.T) {
 		})
 	}
 }
+
+func TestFlattenCollections(t *testing.T) {
+	t.Run("Join string slice", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"id": 1, "tags": []string{"go", "gin", "gorm"}},
+		}
+
+		flattenCollections(rows, ", ")
+
+		if rows[0]["tags"] != "go, gin, gorm" {
+			t.Errorf("Expected tags='go, gin, gorm', got %v", rows[0]["tags"])
+		}
+		if rows[0]["id"] != 1 {
+			t.Errorf("Expected id=1 to be untouched, got %v", rows[0]["id"])
+		}
+	})
+
+	t.Run("Stringify nested map", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"meta": map[string]interface{}{"color": "red", "size": map[string]int{"w": 2}}},
+		}
+
+		flattenCollections(rows, ",")
+
+		expected := `{"color":"red","size":{"w":2}}`
+		if rows[0]["meta"] != expected {
+			t.Errorf("Expected meta=%s, got %v", expected, rows[0]["meta"])
+		}
+	})
+
+	t.Run("Nil collections become empty strings", func(t *testing.T) {
+		var tags []string
+		var meta map[string]interface{}
+		rows := []map[string]interface{}{
+			{"tags": tags, "meta": meta},
+		}
+
+		flattenCollections(rows, ",")
+
+		if rows[0]["tags"] != "" {
+			t.Errorf("Expected empty tags, got %v", rows[0]["tags"])
+		}
+		if rows[0]["meta"] != "" {
+			t.Errorf("Expected empty meta, got %v", rows[0]["meta"])
+		}
+	})
+
+	t.Run("Byte slices are untouched", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"raw": []byte("abc")},
+		}
+
+		flattenCollections(rows, ",")
+
+		if _, ok := rows[0]["raw"].([]byte); !ok {
+			t.Errorf("Expected raw to remain []byte, got %T", rows[0]["raw"])
+		}
+	})
+}
+
+type TestStringOptions struct {
+	ID      int     `json:"id,string"`
+	Active  bool    `json:"active,string"`
+	Score   float64 `json:"score,string"`
+	Code    string  `json:"code,string"`
+	Parent  *int    `json:"parent,string"`
+	Count   int     `json:"count,omitempty"`
+	Ignored []int   `json:"ignored,string"`
+}
+
+func TestStructToMapStringOption(t *testing.T) {
+	item := TestStringOptions{ID: 42, Active: true, Score: 1.5, Code: "abc", Count: 3, Ignored: []int{1}}
+
+	result := structToMap(reflect.ValueOf(item))
+
+	expected := map[string]interface{}{
+		"id":     "42",
+		"active": "true",
+		"score":  "1.5",
+		"code":   `"abc"`,
+		"parent": nil,
+		"count":  3,
+	}
+	for key, want := range expected {
+		if result[key] != want {
+			t.Errorf("Expected %s=%#v, got %#v", key, want, result[key])
+		}
+	}
+
+	// Options other than string-compatible kinds are left as-is
+	if _, ok := result["ignored"].([]int); !ok {
+		t.Errorf("Expected ignored to remain []int, got %T", result["ignored"])
+	}
+
+	// The result matches encoding/json output
+	b, _ := json.Marshal(item)
+	var decoded map[string]interface{}
+	json.Unmarshal(b, &decoded)
+	if decoded["id"] != result["id"] || decoded["active"] != result["active"] || decoded["code"] != result["code"] {
+		t.Errorf("Expected encoding/json semantics, got %s", b)
+	}
+}
+
+func TestNormalizeDriverValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{"Integer bytes", []byte("42"), int64(42)},
+		{"Negative decimal bytes", []byte("-3.50"), -3.5},
+		{"Text bytes", []byte("books"), "books"},
+		{"Leading zeros stay strings", []byte("007"), "007"},
+		{"Exponent stays string", []byte("1e5"), "1e5"},
+		{"int32", int32(7), int64(7)},
+		{"uint8", uint8(7), int64(7)},
+		{"Large uint64", uint64(math.MaxUint64), uint64(math.MaxUint64)},
+		{"float32", float32(1.5), 1.5},
+		{"int64 unchanged", int64(9), int64(9)},
+		{"nil unchanged", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeDriverValue(tt.value); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("normalizeDriverValue(%#v) = %#v, want %#v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeNumbers(t *testing.T) {
+	type level int8
+	type numbers struct {
+		I     int     `json:"i"`
+		I8    int8    `json:"i8"`
+		I16   int16   `json:"i16"`
+		I32   int32   `json:"i32"`
+		I64   int64   `json:"i64"`
+		U     uint    `json:"u"`
+		U8    uint8   `json:"u8"`
+		U16   uint16  `json:"u16"`
+		U32   uint32  `json:"u32"`
+		U64   uint64  `json:"u64"`
+		Big   uint64  `json:"big"`
+		F32   float32 `json:"f32"`
+		F64   float64 `json:"f64"`
+		Level level   `json:"level"`
+		Raw   []byte  `json:"raw"`
+		Name  string  `json:"name"`
+	}
+
+	rows := structToMapSlice([]numbers{{
+		I: -1, I8: -8, I16: 16, I32: 32, I64: 64,
+		U: 1, U8: 8, U16: 16, U32: 32, U64: 64, Big: math.MaxUint64,
+		F32: 0.5, F64: 2.5, Level: 3, Raw: []byte("12"), Name: "x",
+	}})
+	normalizeNumbers(rows)
+
+	expected := map[string]interface{}{
+		"i": int64(-1), "i8": int64(-8), "i16": int64(16), "i32": int64(32), "i64": int64(64),
+		"u": int64(1), "u8": int64(8), "u16": int64(16), "u32": int64(32), "u64": int64(64),
+		"big": uint64(math.MaxUint64), "f32": 0.5, "f64": 2.5,
+		"level": level(3), "raw": []byte("12"), "name": "x",
+	}
+	if !reflect.DeepEqual(rows[0], expected) {
+		t.Errorf("Expected %#v, got %#v", expected, rows[0])
+	}
+}
+
+func TestOfReturnNormalizeNumbers(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 7, Name: "John"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("")
+
+			var seen interface{}
+			opts := NewOptions().
+				WithNormalizeNumbers(enabled).
+				Edit("id", func(value interface{}, row map[string]interface{}) interface{} {
+					seen = value
+					return value
+				})
+
+			var users []TestUser
+			if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expected := interface{}(7)
+			if enabled {
+				expected = int64(7)
+			}
+			if seen != expected {
+				t.Errorf("Expected the callback to see %#v, got %#v", expected, seen)
+			}
+		})
+	}
+}
+
+func TestOfReturnMapDest(t *testing.T) {
+	fake := &fakeDB{
+		count: 2,
+		result: fakeResult{
+			columns: []string{"category", "total", "revenue"},
+			rows: [][]driver.Value{
+				{[]byte("books"), []byte("12"), []byte("340.50")},
+				{"games", int64(3), float64(99)},
+			},
+		},
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("")
+
+	var rows []map[string]interface{}
+	query := db.Table("sales").Select("category, COUNT(*) AS total, SUM(price) AS revenue").Group("category")
+	result, err := OfReturn(c, query, &rows, nil, nil, NewOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data := result.Data.([]map[string]interface{})
+	expected := []map[string]interface{}{
+		{"category": "books", "total": int64(12), "revenue": 340.5, "DT_RowIndex": 1},
+		{"category": "games", "total": int64(3), "revenue": float64(99), "DT_RowIndex": 2},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
@@ -0,0 +1,123 @@
+package datatables
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// CountStrategy counts the records matched by a query. Implementations can
+// count exactly, estimate, or read a maintained statistics table.
+//
+// The query is a fresh session of the filtered query (before ordering and
+// pagination) and may be used directly, e.g. q.Count(&n).
+type CountStrategy interface {
+	Count(q *gorm.DB) (int64, error)
+}
+
+// ExactCount counts with SELECT COUNT(*), the default strategy.
+type ExactCount struct{}
+
+// Count implements CountStrategy.
+func (ExactCount) Count(q *gorm.DB) (int64, error) {
+	var count int64
+	err := countRecords(q, false, &count)
+	return count, err
+}
+
+// SubqueryCount counts the rows of the query wrapped in a subquery, so GROUP BY
+// and DISTINCT queries count the resulting rows (like WithGroupedCount).
+type SubqueryCount struct{}
+
+// Count implements CountStrategy.
+func (SubqueryCount) Count(q *gorm.DB) (int64, error) {
+	var count int64
+	err := countRecords(q, true, &count)
+	return count, err
+}
+
+// ApproxCount estimates the count from the query planner instead of scanning
+// the matched rows, trading accuracy for speed on very large tables.
+// PostgreSQL's "Plan Rows" and MySQL's "rows" (scaled by "filtered") estimates
+// are used; other dialects, and plans that cannot be read, fall back to an exact count.
+type ApproxCount struct{}
+
+// Count implements CountStrategy.
+func (ApproxCount) Count(q *gorm.DB) (int64, error) {
+	switch dialect := resolveDialect(q, Options{}); dialect {
+	case dialectPostgres, dialectMySQL:
+		stmt := q.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]interface{}{}).Statement
+		if stmt.Error != nil {
+			return 0, stmt.Error
+		}
+
+		prefix := "EXPLAIN"
+		if dialect == dialectPostgres {
+			prefix = "EXPLAIN (FORMAT JSON)"
+		}
+
+		// The rendered SQL already holds the driver's placeholders, so it is
+		// run as is rather than re-rendered by Raw
+		plan, err := explainRows(q, prefix+" "+stmt.SQL.String(), stmt.Vars)
+		if err != nil {
+			return 0, err
+		}
+		if estimate, ok := planEstimate(dialect, plan); ok {
+			return estimate, nil
+		}
+	}
+	return ExactCount{}.Count(q)
+}
+
+// planEstimate reads the estimated row count from an EXPLAIN result.
+// Returns false if the plan does not hold an estimate.
+func planEstimate(dialect string, plan []map[string]interface{}) (int64, bool) {
+	if len(plan) == 0 {
+		return 0, false
+	}
+
+	if dialect == dialectPostgres {
+		// A single row holding [{"Plan": {"Plan Rows": 123, ...}}]
+		for _, value := range plan[0] {
+			raw, ok := normalizeDriverValue(value).(string)
+			if !ok {
+				continue
+			}
+
+			var doc []struct {
+				Plan struct {
+					Rows float64 `json:"Plan Rows"`
+				} `json:"Plan"`
+			}
+			if err := json.Unmarshal([]byte(raw), &doc); err == nil && len(doc) > 0 {
+				return int64(doc[0].Plan.Rows), true
+			}
+		}
+		return 0, false
+	}
+
+	// MySQL: the first table's rows estimate, scaled by the filtered percentage
+	rows, ok := planNumber(plan[0]["rows"])
+	if !ok {
+		return 0, false
+	}
+	if filtered, ok := planNumber(plan[0]["filtered"]); ok {
+		rows = rows * filtered / 100
+	}
+	return int64(rows), true
+}
+
+// planNumber converts a numeric EXPLAIN value, as returned by the driver, to a float64.
+func planNumber(value interface{}) (float64, bool) {
+	switch v := normalizeDriverValue(value).(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
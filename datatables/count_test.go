@@ -0,0 +1,234 @@
+package datatables
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// stubCount is a CountStrategy returning a fixed count and recording its query.
+type stubCount struct {
+	count int64
+	err   error
+	sql   string
+}
+
+func (s *stubCount) Count(q *gorm.DB) (int64, error) {
+	s.sql = dryRunSQL(q)
+	return s.count, s.err
+}
+
+func TestOfReturnCountStrategy(t *testing.T) {
+	t.Run("Custom strategy computes the filtered count", func(t *testing.T) {
+		fake := &fakeDB{count: 100, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		strategy := &stubCount{count: 42}
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithCountStrategy(strategy))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.RecordsTotal != 100 || result.RecordsFiltered != 42 {
+			t.Errorf("Expected counts 100/42, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+		}
+		if !strings.Contains(strategy.sql, "WHERE LOWER(name) LIKE LOWER(?)") || strings.Contains(strategy.sql, "LIMIT") {
+			t.Errorf("Expected the filtered query without pagination, got %s", strategy.sql)
+		}
+		if fake.CountQueries() != 1 {
+			t.Errorf("Expected only the total count query, got %d", fake.CountQueries())
+		}
+	})
+
+	t.Run("Strategy errors fail the request", func(t *testing.T) {
+		fake := &fakeDB{count: 1}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		countErr := errors.New("stats table unavailable")
+		var users []TestUser
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithCountStrategy(&stubCount{err: countErr}))
+		if !errors.Is(err, countErr) {
+			t.Errorf("Expected the strategy error, got %v", err)
+		}
+	})
+}
+
+func TestBuiltinCountStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy CountStrategy
+		expected string
+	}{
+		{"Exact", ExactCount{}, "SELECT count(*) FROM `test_users` WHERE name = ?"},
+		{"Subquery", SubqueryCount{}, "SELECT count(*) FROM (SELECT * FROM `test_users` WHERE name = ?) AS dt_count"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 7}
+			db := newFakeGormDB(t, fake)
+
+			count, err := tt.strategy.Count(db.Model(&TestUser{}).Where("name = ?", "john"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count != 7 {
+				t.Errorf("Expected 7, got %d", count)
+			}
+			if sql := fake.Queries()[0].SQL; sql != tt.expected {
+				t.Errorf("Unexpected query:\n got  %s\n want %s", sql, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOfReturnTotalProvider(t *testing.T) {
+	provider := func() (int64, error) { return 5000, nil }
+
+	t.Run("Provider replaces the total count query", func(t *testing.T) {
+		fake := &fakeDB{count: 7, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithTotalProvider(provider))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.RecordsTotal != 5000 || result.RecordsFiltered != 7 {
+			t.Errorf("Expected counts 5000/7, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+		}
+		if fake.CountQueries() != 1 {
+			t.Errorf("Expected only the filtered count query, got %d", fake.CountQueries())
+		}
+	})
+
+	t.Run("Unfiltered requests still count the filtered set", func(t *testing.T) {
+		fake := &fakeDB{count: 7, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("draw=1")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithTotalProvider(provider))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// The provided total is an estimate; pagination follows the real count
+		if result.RecordsTotal != 5000 || result.RecordsFiltered != 7 {
+			t.Errorf("Expected counts 5000/7, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+		}
+		if fake.CountQueries() != 1 {
+			t.Errorf("Expected only the filtered count query, got %d", fake.CountQueries())
+		}
+	})
+
+	t.Run("Provider errors fail the request", func(t *testing.T) {
+		fake := &fakeDB{count: 1}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("draw=1")
+
+		providerErr := errors.New("cache unavailable")
+		var users []TestUser
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithTotalProvider(func() (int64, error) {
+			return 0, providerErr
+		}))
+		if !errors.Is(err, providerErr) {
+			t.Errorf("Expected the provider error, got %v", err)
+		}
+		if len(fake.Queries()) != 0 {
+			t.Errorf("Expected no queries after a provider error, got %v", fake.Queries())
+		}
+	})
+}
+
+func TestApproxCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		plan     fakeResult
+		expected int64
+		explain  string
+	}{
+		{
+			name:     "PostgreSQL plan rows",
+			dialect:  "postgres",
+			plan:     fakeResult{columns: []string{"QUERY PLAN"}, rows: [][]driver.Value{{[]byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1234}}]`)}}},
+			expected: 1234,
+			explain:  "EXPLAIN (FORMAT JSON) SELECT * FROM `test_users` WHERE name = ?",
+		},
+		{
+			name:     "MySQL rows scaled by filtered",
+			dialect:  "mysql",
+			plan:     fakeResult{columns: []string{"id", "rows", "filtered"}, rows: [][]driver.Value{{int64(1), int64(2000), 10.0}}},
+			expected: 200,
+			explain:  "EXPLAIN SELECT * FROM `test_users` WHERE name = ?",
+		},
+		{
+			name:     "Unreadable plan falls back to an exact count",
+			dialect:  "postgres",
+			plan:     fakeResult{columns: []string{"QUERY PLAN"}, rows: [][]driver.Value{{"not json"}}},
+			expected: 9,
+			explain:  "EXPLAIN (FORMAT JSON) SELECT * FROM `test_users` WHERE name = ?",
+		},
+		{
+			name:     "Other dialects count exactly",
+			dialect:  "sqlite",
+			expected: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{}
+			fake.handler = func(_ context.Context, query string, _ []interface{}) (fakeResult, error) {
+				if isCountQuery(query) {
+					return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(9)}}}, nil
+				}
+				return tt.plan, nil
+			}
+			db := newNamedFakeGormDB(t, fake, tt.dialect)
+
+			count, err := ApproxCount{}.Count(db.Model(&TestUser{}).Where("name = ?", "john"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, count)
+			}
+			if tt.explain != "" && fake.Queries()[0].SQL != tt.explain {
+				t.Errorf("Unexpected query:\n got  %s\n want %s", fake.Queries()[0].SQL, tt.explain)
+			}
+		})
+	}
+
+	t.Run("PostgreSQL placeholders are run as rendered", func(t *testing.T) {
+		fake := &fakeDB{result: fakeResult{columns: []string{"QUERY PLAN"}, rows: [][]driver.Value{{[]byte(`[{"Plan": {"Plan Rows": 42}}]`)}}}}
+		db := newDialectFakeGormDB(t, fake, postgresDialector{})
+
+		count, err := ApproxCount{}.Count(db.Model(&TestUser{}).Where("name = ? AND email NOT LIKE '%@example.com' AND email <> ?", "john", ""))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if count != 42 {
+			t.Errorf("Expected 42, got %d", count)
+		}
+
+		queries := fake.Queries()
+		expected := `EXPLAIN (FORMAT JSON) SELECT * FROM "test_users" WHERE name = $1 AND email NOT LIKE '%@example.com' AND email <> $2`
+		if len(queries) != 1 || queries[0].SQL != expected {
+			t.Fatalf("Unexpected queries:\n got  %v\n want %s", queries, expected)
+		}
+		if args := queries[0].Args; len(args) != 2 || args[0] != "john" || args[1] != "" {
+			t.Errorf("Unexpected args: %v", args)
+		}
+	})
+}
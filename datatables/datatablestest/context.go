@@ -0,0 +1,61 @@
+// Package datatablestest provides helpers for testing handlers built on the
+// datatables package.
+//
+// Usage:
+//
+//	c := datatablestest.NewContext(map[string]string{
+//	    "draw":             "1",
+//	    "start":            "0",
+//	    "length":           "10",
+//	    "search[value]":    "john",
+//	    "order[0][column]": "name",
+//	    "order[0][dir]":    "asc",
+//	})
+//	params := datatables.ParseParams(c)
+//
+// The helpers do not change the global Gin mode; set it once for the test
+// binary, e.g. with gin.SetMode(gin.TestMode) in TestMain.
+package datatablestest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewContext builds a Gin context for a GET request whose query string holds
+// the given DataTables parameters, ready to pass to ParseParams or OfReturn.
+// Keys are used as-is, so bracketed names like "search[value]" work unchanged.
+// The response is written to an httptest.ResponseRecorder and is discarded;
+// use NewContextRecorder to inspect it.
+//
+// Parameters:
+//   - params: Query parameters (e.g. "draw", "start", "length", "search[value]")
+//
+// Example:
+//   c := datatablestest.NewContext(map[string]string{"draw": "1", "length": "25"})
+func NewContext(params map[string]string) *gin.Context {
+	c, _ := NewContextRecorder(params)
+	return c
+}
+
+// NewContextRecorder is like NewContext but also returns the recorder the
+// response is written to, for testing handlers that call the Of* helpers.
+//
+// Example:
+//   c, w := datatablestest.NewContextRecorder(map[string]string{"draw": "1"})
+//   handler(c)
+//   // w.Code, w.Body
+func NewContextRecorder(params map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	query := url.Values{}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+	return c, w
+}
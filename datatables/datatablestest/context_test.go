@@ -0,0 +1,74 @@
+package datatablestest_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables"
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/datatablestest"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func TestNewContext(t *testing.T) {
+	c := datatablestest.NewContext(map[string]string{
+		"draw":          "3",
+		"start":         "20",
+		"length":        "10",
+		"search[value]": "john & jane",
+	})
+
+	params := datatables.ParseParams(c)
+
+	if params.Draw != 3 || params.Start != 20 || params.Length != 10 {
+		t.Errorf("Expected draw=3 start=20 length=10, got %+v", params)
+	}
+	if params.Search != "john & jane" {
+		t.Errorf("Expected search to be encoded and decoded intact, got %q", params.Search)
+	}
+	if c.Request.Method != http.MethodGet {
+		t.Errorf("Expected a GET request, got %s", c.Request.Method)
+	}
+}
+
+func TestNewContextRecorder(t *testing.T) {
+	c, w := datatablestest.NewContextRecorder(nil)
+
+	c.JSON(http.StatusOK, gin.H{"draw": 1})
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"draw":1}` {
+		t.Errorf("Expected the response to be recorded, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func ExampleNewContext() {
+	c := datatablestest.NewContext(map[string]string{
+		"draw":             "1",
+		"start":            "10",
+		"length":           "5",
+		"search[value]":    "john",
+		"order[0][column]": "name",
+		"order[0][dir]":    "desc",
+	})
+
+	params := datatables.ParseParams(c)
+	fmt.Println(params.Draw, params.Start, params.Length, params.Search, params.Order, params.Dir)
+	// Output: 1 10 5 john name desc
+}
+
+func ExampleNewContextRecorder() {
+	c, w := datatablestest.NewContextRecorder(map[string]string{"draw": "7"})
+
+	params := datatables.ParseParams(c)
+	c.JSON(http.StatusOK, gin.H{"draw": params.Draw})
+
+	fmt.Println(w.Code, w.Body.String())
+	// Output: 200 {"draw":7}
+}
@@ -0,0 +1,44 @@
+package datatables
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Dialect names recognized by the search and order builders
+const (
+	dialectPostgres  = "postgres"
+	dialectMySQL     = "mysql"
+	dialectSQLite    = "sqlite"
+	dialectSQLServer = "sqlserver"
+)
+
+// resolveDialect returns the SQL dialect used to build queries:
+// opts.Dialect when set, otherwise the name reported by the query's dialector.
+// Names are normalized so wrapped drivers (e.g. "cloudsqlpostgres", "sqlite3")
+// resolve to the dialect they speak. Unknown names are returned lowercased.
+func resolveDialect(query *gorm.DB, opts Options) string {
+	name := opts.Dialect
+	if name == "" && query != nil && query.Dialector != nil {
+		name = query.Dialector.Name()
+	}
+	return normalizeDialect(name)
+}
+
+// normalizeDialect maps a dialector or driver name to a known dialect.
+func normalizeDialect(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	switch {
+	case strings.Contains(name, "postgres"), strings.Contains(name, "pgx"):
+		return dialectPostgres
+	case strings.Contains(name, "mysql"), strings.Contains(name, "mariadb"):
+		return dialectMySQL
+	case strings.Contains(name, "sqlite"):
+		return dialectSQLite
+	case strings.Contains(name, "sqlserver"), strings.Contains(name, "mssql"):
+		return dialectSQLServer
+	}
+	return name
+}
@@ -0,0 +1,107 @@
+package datatables
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils/tests"
+)
+
+// namedDialector is a dry-run dialector reporting a custom name.
+type namedDialector struct {
+	tests.DummyDialector
+	name string
+}
+
+func (d namedDialector) Name() string { return d.name }
+
+// postgresDialector renders SQL like PostgreSQL drivers: numbered "$n"
+// placeholders and double-quoted identifiers.
+type postgresDialector struct {
+	tests.DummyDialector
+}
+
+func (postgresDialector) Name() string { return "postgres" }
+
+func (postgresDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	writer.WriteString("$" + strconv.Itoa(len(stmt.Vars)))
+}
+
+func (postgresDialector) QuoteTo(writer clause.Writer, str string) {
+	writer.WriteString(`"` + str + `"`)
+}
+
+func newNamedDryRunDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(namedDialector{name: name}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	return db
+}
+
+func TestNormalizeDialect(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"postgres", dialectPostgres},
+		{"cloudsqlpostgres", dialectPostgres},
+		{"pgx", dialectPostgres},
+		{"MySQL", dialectMySQL},
+		{"sqlite3", dialectSQLite},
+		{"sqlserver", dialectSQLServer},
+		{"mssql", dialectSQLServer},
+		{"clickhouse", "clickhouse"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeDialect(tt.name); got != tt.expected {
+				t.Errorf("normalizeDialect(%q) = %q, want %q", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveDialect(t *testing.T) {
+	db := newNamedDryRunDB(t, "cloudsqlpostgres")
+
+	if got := resolveDialect(db, NewOptions()); got != dialectPostgres {
+		t.Errorf("Expected detected dialect %q, got %q", dialectPostgres, got)
+	}
+	if got := resolveDialect(db, NewOptions().WithDialect("mysql")); got != dialectMySQL {
+		t.Errorf("Expected overridden dialect %q, got %q", dialectMySQL, got)
+	}
+}
+
+func TestApplySearchDetectsDialect(t *testing.T) {
+	tests := []struct {
+		dialector string
+		override  string
+		expected  string
+	}{
+		{"postgres", "", "TO_CHAR(created_at, ?) LIKE ?"},
+		{"mysql", "", "DATE_FORMAT(created_at, ?) LIKE ?"},
+		{"sqlite", "", "strftime(?, created_at) LIKE ?"},
+		{"custom-wrapper", "", "CAST(created_at AS CHAR(19)) LIKE ?"},
+		{"custom-wrapper", "sqlserver", "FORMAT(created_at, ?) LIKE ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialector+" "+tt.override, func(t *testing.T) {
+			db := newNamedDryRunDB(t, tt.dialector)
+			opts := NewOptions().WithDateSearchColumns("", "created_at").WithDialect(tt.override)
+
+			sql := dryRunSQL(applySearch(db.Model(&TestUser{}), []string{"created_at"}, "2024", opts))
+			if !strings.Contains(sql, tt.expected) {
+				t.Errorf("Expected %q, got %s", tt.expected, sql)
+			}
+		})
+	}
+}
@@ -10,4 +10,110 @@ type Params struct {
 	Search string
 	Order  string
 	Dir    string
-}
\ No newline at end of file
+	Orders []Order // All ordering instructions in priority order (order[0], order[1], ...)
+
+	ColumnSearches []ColumnSearch // Per-column search values (columns[i][search][value])
+
+	Cursor string // Last-seen cursor value for keyset pagination (see Options.WithCursor)
+
+	Columns []string // Client column definitions (columns[i][data], or [name] when data is empty) in index order
+}
+
+// ColumnSearch is a per-column search value sent by DataTables.
+type ColumnSearch struct {
+	Column string
+	Value  string
+}
+
+// Order is a single ordering instruction sent by DataTables.
+type Order struct {
+	Column string
+	Dir    string
+}
+
+// ========================
+// Params Builder
+// ========================
+
+// ParamsBuilder builds Params fluently for programmatic use
+// (server-to-server calls, tests) without a Gin context.
+type ParamsBuilder struct {
+	params Params
+}
+
+// NewParams returns a ParamsBuilder with the same defaults as a request
+// without parameters: draw 1, first page of 10 records, ascending order.
+//
+// Example:
+//   params := dto.NewParams().WithSearch("john").WithPage(2, 25).WithOrder("name", "desc").Build()
+//   // Start: 25, Length: 25
+func NewParams() *ParamsBuilder {
+	return &ParamsBuilder{params: Params{Draw: 1, Length: 10, Dir: "asc"}}
+}
+
+// WithDraw sets the draw counter.
+func (b *ParamsBuilder) WithDraw(draw int64) *ParamsBuilder {
+	b.params.Draw = draw
+	return b
+}
+
+// WithSearch sets the global search value.
+func (b *ParamsBuilder) WithSearch(value string) *ParamsBuilder {
+	b.params.Search = value
+	return b
+}
+
+// WithPage sets pagination from a 1-based page number and a page size,
+// computing Start as (page-1)*size. Pages below 1 are treated as the first page.
+func (b *ParamsBuilder) WithPage(page, size int) *ParamsBuilder {
+	if page < 1 {
+		page = 1
+	}
+	b.params.Start = (page - 1) * size
+	b.params.Length = size
+	return b
+}
+
+// WithOffset sets pagination from a raw offset and length, like DataTables' start/length.
+func (b *ParamsBuilder) WithOffset(start, length int) *ParamsBuilder {
+	b.params.Start = start
+	b.params.Length = length
+	return b
+}
+
+// WithOrder sets the primary order column and direction ("asc" or "desc")
+// and appends it to Orders.
+func (b *ParamsBuilder) WithOrder(column, dir string) *ParamsBuilder {
+	if len(b.params.Orders) == 0 {
+		b.params.Order = column
+		b.params.Dir = dir
+	}
+	b.params.Orders = append(b.params.Orders, Order{Column: column, Dir: dir})
+	return b
+}
+
+// WithColumnSearch appends a per-column search value.
+func (b *ParamsBuilder) WithColumnSearch(column, value string) *ParamsBuilder {
+	b.params.ColumnSearches = append(b.params.ColumnSearches, ColumnSearch{Column: column, Value: value})
+	return b
+}
+
+// WithCursor sets the last-seen cursor value for keyset pagination.
+func (b *ParamsBuilder) WithCursor(cursor string) *ParamsBuilder {
+	b.params.Cursor = cursor
+	return b
+}
+
+// WithColumns sets the client column definitions, in client column order.
+func (b *ParamsBuilder) WithColumns(columns ...string) *ParamsBuilder {
+	b.params.Columns = append([]string(nil), columns...)
+	return b
+}
+
+// Build returns the built Params.
+func (b *ParamsBuilder) Build() Params {
+	params := b.params
+	params.Orders = append([]Order(nil), b.params.Orders...)
+	params.ColumnSearches = append([]ColumnSearch(nil), b.params.ColumnSearches...)
+	return params
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewParams(t *testing.T) {
+	params := NewParams().Build()
+
+	expected := Params{Draw: 1, Length: 10, Dir: "asc"}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Expected defaults %+v, got %+v", expected, params)
+	}
+}
+
+func TestParamsBuilderWithPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		page, size    int
+		start, length int
+	}{
+		{"First page", 1, 25, 0, 25},
+		{"Second page", 2, 25, 25, 25},
+		{"Tenth page of 10", 10, 10, 90, 10},
+		{"Page zero is the first page", 0, 50, 0, 50},
+		{"Negative page is the first page", -3, 10, 0, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := NewParams().WithPage(tt.page, tt.size).Build()
+
+			if params.Start != tt.start || params.Length != tt.length {
+				t.Errorf("WithPage(%d, %d) = start %d, length %d; want %d, %d",
+					tt.page, tt.size, params.Start, params.Length, tt.start, tt.length)
+			}
+		})
+	}
+}
+
+func TestParamsBuilderChaining(t *testing.T) {
+	params := NewParams().
+		WithDraw(3).
+		WithSearch("x").
+		WithPage(2, 25).
+		WithOrder("name", "desc").
+		WithOrder("id", "asc").
+		Build()
+
+	if params.Draw != 3 || params.Search != "x" || params.Start != 25 {
+		t.Errorf("Unexpected params: %+v", params)
+	}
+	if params.Order != "name" || params.Dir != "desc" {
+		t.Errorf("Expected primary order name desc, got %q %q", params.Order, params.Dir)
+	}
+	if len(params.Orders) != 2 || params.Orders[1].Column != "id" {
+		t.Errorf("Expected 2 orders, got %v", params.Orders)
+	}
+}
+
+func TestParamsBuilderColumnSearch(t *testing.T) {
+	builder := NewParams().WithColumnSearch("price", ">100")
+	params := builder.Build()
+	builder.WithColumnSearch("name", "jo")
+
+	if len(params.ColumnSearches) != 1 || params.ColumnSearches[0] != (ColumnSearch{Column: "price", Value: ">100"}) {
+		t.Errorf("Expected one column search unaffected by later calls, got %v", params.ColumnSearches)
+	}
+}
@@ -1,6 +1,9 @@
 package dto
 
 import (
+	"reflect"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,10 +14,64 @@ import (
 // Datatables represents the standard response structure used by the
 // jQuery DataTables plugin. It includes pagination metadata and data rows.
 type Datatables struct {
-	Draw            int64       `json:"draw"`            // Draw counter to synchronize client-side and server-side data
-	RecordsTotal    int64       `json:"recordsTotal"`    // Total number of records available
-	RecordsFiltered int64       `json:"recordsFiltered"` // Number of records after applying filters
-	Data            interface{} `json:"data"`            // Actual data rows to be displayed in the DataTable
+	Draw            int64                  `json:"draw"`                     // Draw counter to synchronize client-side and server-side data
+	RecordsTotal    int64                  `json:"recordsTotal"`             // Total number of records available
+	RecordsFiltered int64                  `json:"recordsFiltered"`          // Number of records after applying filters
+	Data            interface{}            `json:"data"`                     // Actual data rows to be displayed in the DataTable
+	Error           string                 `json:"error,omitempty"`          // Optional error message displayed by DataTables instead of the data
+	Message         string                 `json:"message,omitempty"`        // Optional message for empty results (see Options.WithEmptyMessage)
+	RecordsDropped  int                    `json:"recordsDropped,omitempty"` // Rows dropped from the page by Options.WithRowFilter
+	NextCursor      interface{}            `json:"nextCursor,omitempty"`     // Cursor of the next page in keyset mode (see Options.WithCursor)
+	Timings         *Timings               `json:"DT_Timings,omitempty"`     // Durations of the database calls (see Options.WithTimings)
+	Truncated       bool                   `json:"truncated,omitempty"`      // Set when Options.WithResultLimit cut the result short
+	Footer          map[string]interface{} `json:"footer,omitempty"`         // Aggregates over the filtered set (see Options.WithFooterAggregate)
+}
+
+// Timings holds the durations of the database calls of a request, in
+// milliseconds. Calls that did not run (such as a skipped filtered count)
+// report 0.
+type Timings struct {
+	TotalCount    float64 `json:"totalCount"`    // Unfiltered count (recordsTotal)
+	FilteredCount float64 `json:"filteredCount"` // Filtered count (recordsFiltered)
+	Fetch         float64 `json:"fetch"`         // Fetch of the page rows
+}
+
+// Rows returns the data rows as maps. Array data rows ([][]interface{}, see
+// WithArrayData) are converted to maps keyed by position ("0", "1", ...),
+// like DataTables' integer columns[].data. Returns nil for other Data types.
+func (d Datatables) Rows() []map[string]interface{} {
+	switch data := d.Data.(type) {
+	case []map[string]interface{}:
+		return data
+	case [][]interface{}:
+		rows := make([]map[string]interface{}, len(data))
+		for i, values := range data {
+			row := make(map[string]interface{}, len(values))
+			for j, v := range values {
+				row[strconv.Itoa(j)] = v
+			}
+			rows[i] = row
+		}
+		return rows
+	}
+	return nil
+}
+
+// Len returns the number of data rows, for map rows, array data rows, or
+// any other slice. Returns 0 when Data is nil or not a slice.
+func (d Datatables) Len() int {
+	switch data := d.Data.(type) {
+	case []map[string]interface{}:
+		return len(data)
+	case [][]interface{}:
+		return len(data)
+	case nil:
+		return 0
+	}
+	if v := reflect.ValueOf(d.Data); v.Kind() == reflect.Slice {
+		return v.Len()
+	}
+	return 0
 }
 
 // ========================
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDatatablesRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   interface{}
+		rows   []map[string]interface{}
+		length int
+	}{
+		{
+			name:   "Map rows",
+			data:   []map[string]interface{}{{"id": 1}, {"id": 2}},
+			rows:   []map[string]interface{}{{"id": 1}, {"id": 2}},
+			length: 2,
+		},
+		{
+			name:   "Array data rows",
+			data:   [][]interface{}{{1, "John"}, {2, nil}},
+			rows:   []map[string]interface{}{{"0": 1, "1": "John"}, {"0": 2, "1": nil}},
+			length: 2,
+		},
+		{
+			name:   "Other slice",
+			data:   []string{"a", "b", "c"},
+			rows:   nil,
+			length: 3,
+		},
+		{
+			name:   "Nil data",
+			data:   nil,
+			rows:   nil,
+			length: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Datatables{Data: tt.data}
+
+			if rows := d.Rows(); !reflect.DeepEqual(rows, tt.rows) {
+				t.Errorf("Rows() = %v, want %v", rows, tt.rows)
+			}
+			if n := d.Len(); n != tt.length {
+				t.Errorf("Len() = %d, want %d", n, tt.length)
+			}
+		})
+	}
+}
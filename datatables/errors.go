@@ -1,6 +1,9 @@
 package datatables
 
-import "errors"
+import (
+	"errors"
+	"strconv"
+)
 
 // Common errors returned by the datatables package
 var (
@@ -15,6 +18,18 @@ var (
 
 	// ErrDefaultOrderColumn is returned when default ordering references a non-existent column
 	ErrDefaultOrderColumn = errors.New("default order column does not exist in the database")
+
+	// ErrQueryTimeout is reported when the database calls exceed Options.QueryTimeout
+	ErrQueryTimeout = errors.New("query timed out")
+
+	// ErrDebugDisabled is returned by debugging helpers such as ExplainPlan unless Options.Debug is set
+	ErrDebugDisabled = errors.New("debugging helpers are disabled: enable them with WithDebug")
+
+	// ErrExplainUnsupported is returned by ExplainPlan for dialects without an EXPLAIN statement
+	ErrExplainUnsupported = errors.New("explain is not supported for this database dialect")
+
+	// ErrCountMismatch is matched by CountMismatchError, returned in strict count mode
+	ErrCountMismatch = errors.New("filtered count exceeds total count")
 )
 
 // ValidationError represents a validation error with additional context
@@ -26,3 +41,21 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return "validation error on field '" + e.Field + "': " + e.Message
 }
+
+// CountMismatchError reports a filtered count greater than the total count,
+// which almost always means a join multiplies rows: the base query needs
+// Distinct, or a grouped count (see WithGroupedCount).
+type CountMismatchError struct {
+	Total    int64
+	Filtered int64
+}
+
+func (e *CountMismatchError) Error() string {
+	return ErrCountMismatch.Error() + " (recordsFiltered=" + strconv.FormatInt(e.Filtered, 10) +
+		", recordsTotal=" + strconv.FormatInt(e.Total, 10) + "): the base query probably needs Distinct or a grouped count"
+}
+
+// Is makes errors.Is(err, ErrCountMismatch) match a CountMismatchError.
+func (e *CountMismatchError) Is(target error) bool {
+	return target == ErrCountMismatch
+}
@@ -0,0 +1,124 @@
+package datatables
+
+import (
+	"context"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"gorm.io/gorm"
+)
+
+// ExplainPlan runs the database's EXPLAIN on the query OfParams would use to
+// fetch the requested page (after search, ordering, and pagination) and returns
+// the plan rows, to diagnose slow tables without leaving the app.
+//
+// It is disabled unless opts enables debugging with WithDebug(true), so it can
+// be wired into an endpoint that stays inert in production builds.
+// The EXPLAIN syntax depends on the dialect:
+//   - postgres, mysql: EXPLAIN <query>
+//   - sqlite:          EXPLAIN QUERY PLAN <query>
+//   - sqlserver:       not supported (returns ErrExplainUnsupported)
+//
+// The page query is built by the same code as OfParams, so the ColumnACL,
+// HAVING conditions, cursor, and page and offset limits all apply. The EXPLAIN
+// runs like the page fetch of OfParams: bounded by the query timeout, retried
+// on transient errors, and on the FetchDB handle when set.
+//
+// Returns ErrDebugDisabled when debugging is not enabled.
+//
+// Example:
+//   params := dto.NewParams().WithSearch("john").WithOrder("name", "asc").Build()
+//   plan, err := datatables.ExplainPlan(db.Model(&User{}), &users, params, searchable, orderable,
+//       opts.WithDebug(gin.IsDebugging()))
+func ExplainPlan[T any](
+	query *gorm.DB,
+	dest *[]T,
+	params dto.Params,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+) ([]map[string]interface{}, error) {
+	if !opts.Debug {
+		return nil, ErrDebugDisabled
+	}
+
+	model := query.Statement.Model
+	if model == nil {
+		model = dest
+	}
+	opts.Dialect = resolveDialect(query, opts)
+	prefix, ok := explainPrefix(opts.Dialect)
+	if !ok {
+		return nil, ErrExplainUnsupported
+	}
+
+	// Build the fetch query with the same builder as OfParams
+	req, err := resolveRequest(nil, query, model, normalizeParams(params, maxPageSize(nil, opts)), searchable, orderable, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts = req.opts
+	pageQuery, _ := req.filter(nil, query, false)
+	if pageQuery, err = req.page(pageQuery); err != nil {
+		return nil, err
+	}
+
+	// Render the SQL without executing it, then explain it with the driver's placeholders
+	stmt := pageQuery.Session(&gorm.Session{DryRun: true}).Find(dest).Statement
+	if stmt.Error != nil {
+		return nil, stmt.Error
+	}
+
+	// Explain where the page is fetched, as the fetch would run
+	if opts.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		query, cancel = withQueryTimeout(nil, query, opts.QueryTimeout)
+		defer cancel()
+	}
+	explainQuery := query
+	if opts.FetchDB != nil {
+		explainQuery = onHandle(query, opts.FetchDB)
+	}
+
+	var plan []map[string]interface{}
+	err = withRetry(queryContext(query), opts, func() error {
+		plan, err = explainRows(explainQuery, prefix+" "+stmt.SQL.String(), stmt.Vars)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// explainRows runs the rendered EXPLAIN statement on the query's connection
+// pool and scans each plan row into a map.
+func explainRows(query *gorm.DB, sql string, vars []interface{}) ([]map[string]interface{}, error) {
+	rows, err := query.Statement.ConnPool.QueryContext(queryContext(query), sql, vars...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plan := []map[string]interface{}{}
+	for rows.Next() {
+		row := map[string]interface{}{}
+		if err := query.ScanRows(rows, &row); err != nil {
+			return nil, err
+		}
+		plan = append(plan, row)
+	}
+
+	return plan, rows.Err()
+}
+
+// explainPrefix returns the EXPLAIN statement prefix for the dialect.
+// Returns false if the dialect has no EXPLAIN statement.
+func explainPrefix(dialect string) (string, bool) {
+	switch dialect {
+	case dialectSQLite:
+		return "EXPLAIN QUERY PLAN", true
+	case dialectSQLServer:
+		return "", false
+	}
+	return "EXPLAIN", true
+}
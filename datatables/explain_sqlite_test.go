@@ -0,0 +1,47 @@
+//go:build sqlite
+
+package datatables
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// Run with: go test -tags sqlite ./...
+func TestExplainPlanSQLite(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&TestUser{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := db.Create(&[]TestUser{{Name: "John", Email: "john@example.com"}, {Name: "Jane", Email: "jane@example.com"}}).Error; err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	params := dto.NewParams().WithSearch("jo").WithPage(1, 10).WithOrder("name", "desc").Build()
+	opts := NewOptions().WithDebug(true)
+
+	var users []TestUser
+	plan, err := ExplainPlan(db.Model(&TestUser{}), &users, params, []string{"name", "email"}, map[string]string{"name": "name"}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plan) == 0 {
+		t.Fatal("Expected plan rows, got none")
+	}
+
+	var details []string
+	for _, row := range plan {
+		detail, _ := row["detail"].(string)
+		details = append(details, detail)
+	}
+	if joined := strings.Join(details, "\n"); !strings.Contains(joined, "test_users") {
+		t.Errorf("Expected the plan to scan test_users, got %q", joined)
+	}
+}
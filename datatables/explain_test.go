@@ -0,0 +1,188 @@
+package datatables
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// newNamedFakeGormDB opens a GORM connection backed by a fakeDB whose
+// dialector reports the given name.
+func newNamedFakeGormDB(t *testing.T, fake *fakeDB, name string) *gorm.DB {
+	t.Helper()
+	return newDialectFakeGormDB(t, fake, namedDialector{name: name})
+}
+
+// newDialectFakeGormDB opens a GORM connection backed by a fakeDB that
+// renders SQL with the given dialector.
+func newDialectFakeGormDB(t *testing.T, fake *fakeDB, dialector gorm.Dialector) *gorm.DB {
+	t.Helper()
+
+	sqlDB := sql.OpenDB(fakeConnector{fake: fake})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(dialector, &gorm.Config{ConnPool: sqlDB})
+	if err != nil {
+		t.Fatalf("failed to open fake database: %v", err)
+	}
+	return db
+}
+
+func TestExplainPlan(t *testing.T) {
+	fake := &fakeDB{
+		handler: func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+			return fakeResult{
+				columns: []string{"id", "parent", "notused", "detail"},
+				rows:    [][]driver.Value{{int64(2), int64(0), int64(0), "SCAN test_users"}},
+			}, nil
+		},
+	}
+	db := newNamedFakeGormDB(t, fake, "sqlite")
+
+	params := dto.NewParams().WithSearch("jo").WithPage(2, 10).WithOrder("name", "desc").Build()
+	opts := NewOptions().WithDebug(true)
+
+	var users []TestUser
+	plan, err := ExplainPlan(db.Model(&TestUser{}), &users, params, []string{"name"}, map[string]string{"name": "name"}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 1 {
+		t.Fatalf("Expected a single query, got %d", len(queries))
+	}
+
+	expected := "EXPLAIN QUERY PLAN SELECT * FROM `test_users` WHERE LOWER(name) LIKE LOWER(?) ORDER BY name desc LIMIT ? OFFSET ?"
+	if queries[0].SQL != expected {
+		t.Errorf("Unexpected query:\n got  %s\n want %s", queries[0].SQL, expected)
+	}
+	if args := queries[0].Args; len(args) != 3 || args[0] != "%jo%" {
+		t.Errorf("Unexpected args: %v", args)
+	}
+
+	if len(plan) != 1 || plan[0]["detail"] != "SCAN test_users" {
+		t.Errorf("Unexpected plan: %v", plan)
+	}
+}
+
+func TestExplainPlanMatchesOfParams(t *testing.T) {
+	params := dto.NewParams().WithSearch("jo").WithPage(1, 500).WithOrder("name", "desc").WithCursor("40").Build()
+	searchable := []string{"name", "email"}
+	orderable := map[string]string{"name": "name"}
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"Cursor with a multi-column search", NewOptions().WithCursor("id")},
+		{"Having", NewOptions().WithHaving("COUNT(*) > ?", 1)},
+		{"Result limit", NewOptions().WithResultLimit(20)},
+		{"ACL-hidden columns", NewOptions().WithColumnACL(func(c *gin.Context, column string) bool { return column != "email" })},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched := &fakeDB{count: 1}
+			var users []TestUser
+			if _, err := OfParams(newNamedFakeGormDB(t, fetched, "sqlite").Model(&TestUser{}), &users, params, searchable, orderable, tt.opts); err != nil {
+				t.Fatalf("Unexpected OfParams error: %v", err)
+			}
+
+			explained := &fakeDB{}
+			if _, err := ExplainPlan(newNamedFakeGormDB(t, explained, "sqlite").Model(&TestUser{}), &users, params, searchable, orderable, tt.opts.WithDebug(true)); err != nil {
+				t.Fatalf("Unexpected ExplainPlan error: %v", err)
+			}
+
+			expected := fetched.LastSelect()
+			got := explained.Queries()[0]
+			if got.SQL != "EXPLAIN QUERY PLAN "+expected.SQL {
+				t.Errorf("Unexpected query:\n got  %s\n want EXPLAIN QUERY PLAN %s", got.SQL, expected.SQL)
+			}
+			if !reflect.DeepEqual(got.Args, expected.Args) {
+				t.Errorf("Expected args %v, got %v", expected.Args, got.Args)
+			}
+		})
+	}
+}
+
+func TestExplainPlanGuards(t *testing.T) {
+	var users []TestUser
+	params := dto.NewParams().Build()
+
+	t.Run("Disabled without debug", func(t *testing.T) {
+		fake := &fakeDB{}
+		db := newNamedFakeGormDB(t, fake, "postgres")
+
+		if _, err := ExplainPlan(db.Model(&TestUser{}), &users, params, nil, nil, NewOptions()); err != ErrDebugDisabled {
+			t.Errorf("Expected ErrDebugDisabled, got %v", err)
+		}
+		if len(fake.Queries()) != 0 {
+			t.Errorf("Expected no queries, got %v", fake.Queries())
+		}
+	})
+
+	t.Run("Unsupported dialect", func(t *testing.T) {
+		db := newNamedFakeGormDB(t, &fakeDB{}, "sqlserver")
+
+		if _, err := ExplainPlan(db.Model(&TestUser{}), &users, params, nil, nil, NewOptions().WithDebug(true)); err != ErrExplainUnsupported {
+			t.Errorf("Expected ErrExplainUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("Postgres uses EXPLAIN", func(t *testing.T) {
+		fake := &fakeDB{result: fakeResult{columns: []string{"QUERY PLAN"}}}
+		db := newNamedFakeGormDB(t, fake, "postgres")
+
+		if _, err := ExplainPlan(db.Model(&TestUser{}), &users, params, nil, nil, NewOptions().WithDebug(true)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if sql := fake.Queries()[0].SQL; !strings.HasPrefix(sql, "EXPLAIN SELECT") {
+			t.Errorf("Expected EXPLAIN prefix, got %s", sql)
+		}
+	})
+
+	t.Run("Retries transient errors", func(t *testing.T) {
+		failed := false
+		fake := &fakeDB{}
+		fake.handler = func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+			if !failed {
+				failed = true
+				return fakeResult{}, io.ErrUnexpectedEOF
+			}
+			return fakeResult{columns: []string{"QUERY PLAN"}, rows: [][]driver.Value{{"Seq Scan"}}}, nil
+		}
+		db := newNamedFakeGormDB(t, fake, "postgres")
+
+		plan, err := ExplainPlan(db.Model(&TestUser{}), &users, params, nil, nil, NewOptions().WithDebug(true).WithRetry(2, time.Millisecond))
+		if err != nil {
+			t.Fatalf("Expected retry to succeed, got %v", err)
+		}
+		if len(plan) != 1 || len(fake.Queries()) != 2 {
+			t.Errorf("Expected one plan row after 2 attempts, got %v after %d", plan, len(fake.Queries()))
+		}
+	})
+
+	t.Run("Runs on the fetch handle", func(t *testing.T) {
+		replica := &fakeDB{}
+		primary := &fakeDB{result: fakeResult{columns: []string{"QUERY PLAN"}}}
+		db := newNamedFakeGormDB(t, replica, "postgres")
+
+		opts := NewOptions().WithDebug(true).WithFetchDB(newNamedFakeGormDB(t, primary, "postgres"))
+		if _, err := ExplainPlan(db.Model(&TestUser{}), &users, params, nil, nil, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(replica.Queries()) != 0 || len(primary.Queries()) != 1 {
+			t.Errorf("Expected the EXPLAIN on the fetch handle, got %d replica and %d primary queries", len(replica.Queries()), len(primary.Queries()))
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package datatables
+
+import (
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ParamFilter applies a condition on a database column from a request parameter.
+type ParamFilter struct {
+	Param    string // Request parameter name (e.g. "status")
+	Column   string // Database column, validated with isValidColumnName
+	Operator string // One of eq, like, gt, lt, gte, lte
+}
+
+// paramFilterOperators maps the supported operators to their SQL comparison
+var paramFilterOperators = map[string]string{
+	"eq":  "=",
+	"gt":  ">",
+	"lt":  "<",
+	"gte": ">=",
+	"lte": "<=",
+}
+
+// applyParamFilters adds a condition for every filter whose parameter is
+// present and non-empty in values (the request parameters, see paramValues).
+// The value is always passed as a bind parameter; "like" is a
+// case-insensitive substring match like the global search.
+//
+// Returns the query and whether any filter was applied.
+func applyParamFilters(values url.Values, query *gorm.DB, filters []ParamFilter) (*gorm.DB, bool) {
+	applied := false
+	for _, filter := range filters {
+		value := valueOrDefault(values, filter.Param, "")
+		if value == "" {
+			continue
+		}
+
+		if filter.Operator == "like" {
+			query = query.Where("LOWER("+filter.Column+") LIKE LOWER(?)", "%"+value+"%")
+		} else {
+			query = query.Where(filter.Column+" "+paramFilterOperators[filter.Operator]+" ?", value)
+		}
+		applied = true
+	}
+	return query, applied
+}
+
+// validateParamFilters checks filter columns and operators.
+func validateParamFilters(filters []ParamFilter) error {
+	for _, filter := range filters {
+		if !isValidColumnName(filter.Column) {
+			return &ValidationError{
+				Field:   filter.Column,
+				Message: "param filter column name contains invalid characters" + invalidColumnDetail(filter.Column),
+			}
+		}
+		if _, ok := paramFilterOperators[filter.Operator]; !ok && filter.Operator != "like" {
+			return &ValidationError{
+				Field:   filter.Column,
+				Message: "unsupported param filter operator '" + filter.Operator + "' (allowed: " + strings.Join(paramFilterOperatorNames, ", ") + ")",
+			}
+		}
+	}
+	return nil
+}
+
+// paramFilterOperatorNames lists the supported operators for error messages
+var paramFilterOperatorNames = []string{"eq", "like", "gt", "lt", "gte", "lte"}
@@ -0,0 +1,159 @@
+package datatables
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWithParamFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator string
+		rawQuery string
+		where    string
+		args     []interface{}
+	}{
+		{"eq present", "eq", "status=active", " WHERE status = ?", []interface{}{"active"}},
+		{"eq absent", "eq", "", "", nil},
+		{"like present", "like", "status=act", " WHERE LOWER(status) LIKE LOWER(?)", []interface{}{"%act%"}},
+		{"like absent", "like", "other=act", "", nil},
+		{"gt present", "gt", "status=5", " WHERE status > ?", []interface{}{"5"}},
+		{"gt absent", "gt", "status=", "", nil},
+		{"lt present", "lt", "status=5", " WHERE status < ?", []interface{}{"5"}},
+		{"lt absent", "lt", "", "", nil},
+		{"gte present", "gte", "status=5", " WHERE status >= ?", []interface{}{"5"}},
+		{"gte absent", "gte", "", "", nil},
+		{"lte present", "lte", "status=5", " WHERE status <= ?", []interface{}{"5"}},
+		{"lte absent", "lte", "", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext(tt.rawQuery)
+
+			opts := NewOptions().WithParamFilter("status", "status", tt.operator)
+
+			var users []TestUser
+			if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			last := fake.LastSelect()
+			expected := "SELECT * FROM `test_users`" + tt.where + " LIMIT ?"
+			if last.SQL != expected {
+				t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+			}
+			if args := last.Args[:len(last.Args)-1]; len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+				t.Errorf("Expected args %v, got %v", tt.args, args)
+			}
+
+			// The filtered count only runs when a filter applies
+			expectedCounts := 1
+			if tt.where != "" {
+				expectedCounts = 2
+			}
+			if n := fake.CountQueries(); n != expectedCounts {
+				t.Errorf("Expected %d count queries, got %d", expectedCounts, n)
+			}
+		})
+	}
+}
+
+func TestWithParamFilterChainedWithSearch(t *testing.T) {
+	fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("search[value]=jo&status=active&min_id=3")
+
+	opts := NewOptions().
+		WithParamFilter("status", "users.status", "eq").
+		WithParamFilter("min_id", "id", "gte")
+
+	var users []TestUser
+	if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name", "email"}, nil, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	last := fake.LastSelect()
+	expected := "SELECT * FROM `test_users` WHERE (LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)) AND users.status = ? AND id >= ? LIMIT ?"
+	if last.SQL != expected {
+		t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+	}
+	if !reflect.DeepEqual(last.Args, []interface{}{"%jo%", "%jo%", "active", "3", int64(10)}) {
+		t.Errorf("Unexpected args: %v", last.Args)
+	}
+}
+
+func TestWithParamFilterSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     func() *gin.Context
+		opts  Options
+		where string
+		args  []interface{}
+	}{
+		{"Form source reads the body", func() *gin.Context { return newFormContext("status=body", "status=query") },
+			NewOptions().WithParamSource(ParamSourceForm), " WHERE status = ?", []interface{}{"body"}},
+		{"Form source ignores the query string", func() *gin.Context { return newFormContext("", "status=query") },
+			NewOptions().WithParamSource(ParamSourceForm), "", nil},
+		{"Query source ignores the body", func() *gin.Context { return newFormContext("status=body", "") },
+			NewOptions().WithParamSource(ParamSourceQuery), "", nil},
+		{"Search prefix", func() *gin.Context { c, _ := newTestContext("t1_status=mine&status=other"); return c },
+			NewOptions().WithSearchPrefix("t1_"), " WHERE status = ?", []interface{}{"mine"}},
+		{"Search prefix ignores unprefixed parameters", func() *gin.Context { c, _ := newTestContext("status=other"); return c },
+			NewOptions().WithSearchPrefix("t1_"), "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+			db := newFakeGormDB(t, fake)
+
+			var users []TestUser
+			opts := tt.opts.WithParamFilter("status", "status", "eq")
+			if _, err := OfReturn(tt.c(), db.Model(&TestUser{}), &users, nil, nil, opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			last := fake.LastSelect()
+			expected := "SELECT * FROM `test_users`" + tt.where + " LIMIT ?"
+			if last.SQL != expected {
+				t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+			}
+			if args := last.Args[:len(last.Args)-1]; len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+				t.Errorf("Expected args %v, got %v", tt.args, args)
+			}
+		})
+	}
+}
+
+func TestWithParamFilterValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		column string
+		op     string
+	}{
+		{"Invalid column", "status; DROP TABLE users", "eq"},
+		{"Unknown operator", "status", "between"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("status=active")
+
+			var users []TestUser
+			_, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithParamFilter("status", tt.column, tt.op))
+			if _, ok := err.(*ValidationError); !ok {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+			if len(fake.Queries()) != 0 {
+				t.Errorf("Expected no queries, got %v", fake.Queries())
+			}
+		})
+	}
+}
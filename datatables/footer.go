@@ -0,0 +1,42 @@
+package datatables
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// FooterAggregate is a value computed over the whole filtered set for the
+// response footer (see Options.WithFooterAggregate).
+type FooterAggregate struct {
+	Key  string // Output key in the footer, validated with isValidColumnName
+	Expr string // Developer-trusted SQL aggregate (e.g. "SUM(amount)")
+}
+
+// footerAggregates computes the footer aggregates over the filtered query
+// with a single query. Grouped queries are aggregated through a subquery.
+//
+// Returns the footer values keyed by FooterAggregate.Key.
+func footerAggregates(query *gorm.DB, aggregates []FooterAggregate, grouped bool) (map[string]interface{}, error) {
+	selects := make([]string, len(aggregates))
+	for i, agg := range aggregates {
+		selects[i] = agg.Expr + " AS " + agg.Key
+	}
+
+	aggregateQuery := query.Session(&gorm.Session{})
+	if grouped {
+		aggregateQuery = query.Session(&gorm.Session{NewDB: true}).
+			Table("(?) AS dt_footer", query.Session(&gorm.Session{}))
+	}
+
+	row := map[string]interface{}{}
+	if err := aggregateQuery.Select(strings.Join(selects, ", ")).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+
+	footer := make(map[string]interface{}, len(aggregates))
+	for _, agg := range aggregates {
+		footer[agg.Key] = normalizeDriverValue(row[agg.Key])
+	}
+	return footer, nil
+}
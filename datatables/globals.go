@@ -0,0 +1,87 @@
+package datatables
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponder writes an error response for a failed DataTables request.
+type ErrorResponder func(c *gin.Context, status int, err error)
+
+// Package-level defaults shared by every OfReturn call.
+var (
+	globalMu        sync.RWMutex
+	globalRemove    []string
+	globalResponder ErrorResponder
+	globalGzip      int
+)
+
+// SetGlobalRemove sets the columns removed from the output of every table,
+// such as "password" or "deleted_at". The list is merged with the per-Options
+// RemoveColumns: per-Options removes add to, rather than replace, the global list.
+//
+// Calling SetGlobalRemove again replaces the previous global list.
+// Call it with no arguments to clear it.
+//
+// Example:
+//   func init() {
+//       datatables.SetGlobalRemove("password", "deleted_at")
+//   }
+func SetGlobalRemove(cols ...string) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalRemove = append([]string(nil), cols...)
+}
+
+// globalRemoveColumns returns a copy of the global remove list.
+func globalRemoveColumns() []string {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return append([]string(nil), globalRemove...)
+}
+
+// SetErrorResponder overrides how RespondError writes error responses, so the
+// package can integrate with an existing API error contract
+// (e.g. {code, message, details}). Pass nil to restore the default JSONError format.
+//
+// Example:
+//   datatables.SetErrorResponder(func(c *gin.Context, status int, err error) {
+//       c.JSON(status, gin.H{"code": status, "message": err.Error()})
+//   })
+func SetErrorResponder(fn ErrorResponder) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalResponder = fn
+}
+
+// errorResponder returns the configured error responder, or nil if unset.
+func errorResponder() ErrorResponder {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return globalResponder
+}
+
+// SetGzipThreshold enables gzip compression of JSON, JSONStatus and JSONRaw
+// responses whose encoded payload is at least the given number of bytes,
+// for clients sending "Accept-Encoding: gzip". Other clients and smaller
+// payloads receive plain JSON. Pass 0 to disable compression (the default).
+//
+// Do not combine it with a compression middleware, which would compress twice.
+//
+// Example:
+//   func init() {
+//       datatables.SetGzipThreshold(64 * 1024)
+//   }
+func SetGzipThreshold(bytes int) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalGzip = bytes
+}
+
+// gzipThreshold returns the configured compression threshold, or 0 if disabled.
+func gzipThreshold() int {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return globalGzip
+}
@@ -0,0 +1,104 @@
+package datatables
+
+import "reflect"
+
+// Row keys flagging the marker rows added by WithGroupTotals
+const (
+	groupTotalKey = "DT_GroupTotal"
+	grandTotalKey = "DT_GrandTotal"
+)
+
+// appendGroupTotals inserts a subtotal row after each run of consecutive rows
+// sharing the same group column value, and a grand total row at the end.
+// Rows must already be ordered by the group column for runs to match groups.
+//
+// Subtotal rows hold the group value, the sums and DT_GroupTotal=true; the
+// grand total row additionally holds DT_GrandTotal=true and no group value.
+// Sums stay int64 while every summed value is an integer, and become float64
+// otherwise. Non-numeric values are skipped.
+//
+// Only the rows of the current page are aggregated.
+func appendGroupTotals(rows []map[string]interface{}, groupColumn string, sumColumns []string) []map[string]interface{} {
+	if len(rows) == 0 {
+		return rows
+	}
+
+	out := make([]map[string]interface{}, 0, len(rows)+2)
+	grand := newGroupSums(sumColumns)
+	group := newGroupSums(sumColumns)
+	groupValue := rows[0][groupColumn]
+
+	for _, row := range rows {
+		if !reflect.DeepEqual(row[groupColumn], groupValue) {
+			out = append(out, group.row(groupColumn, groupValue))
+			group = newGroupSums(sumColumns)
+			groupValue = row[groupColumn]
+		}
+
+		out = append(out, row)
+		group.add(row)
+		grand.add(row)
+	}
+	out = append(out, group.row(groupColumn, groupValue))
+
+	total := grand.row("", nil)
+	total[grandTotalKey] = true
+	out = append(out, total)
+
+	return out
+}
+
+// groupSums accumulates the sums of a group of rows.
+type groupSums struct {
+	columns []string
+	ints    map[string]int64
+	floats  map[string]float64
+	isFloat map[string]bool
+}
+
+func newGroupSums(columns []string) *groupSums {
+	return &groupSums{
+		columns: columns,
+		ints:    make(map[string]int64, len(columns)),
+		floats:  make(map[string]float64, len(columns)),
+		isFloat: make(map[string]bool, len(columns)),
+	}
+}
+
+// add adds the row's numeric values to the sums.
+func (g *groupSums) add(row map[string]interface{}) {
+	for _, col := range g.columns {
+		if !isNumeric(row[col]) {
+			continue
+		}
+
+		v := reflect.ValueOf(row[col])
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			g.isFloat[col] = true
+			g.floats[col] += v.Float()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			g.ints[col] += int64(v.Uint())
+			g.floats[col] += float64(v.Uint())
+		default:
+			g.ints[col] += v.Int()
+			g.floats[col] += float64(v.Int())
+		}
+	}
+}
+
+// row builds the marker row holding the sums.
+func (g *groupSums) row(groupColumn string, groupValue interface{}) map[string]interface{} {
+	row := map[string]interface{}{groupTotalKey: true}
+	if groupColumn != "" {
+		row[groupColumn] = groupValue
+	}
+	for _, col := range g.columns {
+		if g.isFloat[col] {
+			row[col] = g.floats[col]
+		} else {
+			row[col] = g.ints[col]
+		}
+	}
+	return row
+}
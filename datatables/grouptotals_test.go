@@ -0,0 +1,83 @@
+package datatables
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestAppendGroupTotals(t *testing.T) {
+	t.Run("Subtotals per group and grand total", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"account": "cash", "debit": int64(10), "credit": 1.5},
+			{"account": "cash", "debit": int64(5), "credit": 2.0},
+			{"account": "bank", "debit": int64(7), "credit": nil},
+		}
+
+		result := appendGroupTotals(rows, "account", []string{"debit", "credit"})
+
+		expected := []map[string]interface{}{
+			rows[0],
+			rows[1],
+			{"account": "cash", "debit": int64(15), "credit": 3.5, "DT_GroupTotal": true},
+			rows[2],
+			{"account": "bank", "debit": int64(7), "credit": int64(0), "DT_GroupTotal": true},
+			{"debit": int64(22), "credit": 3.5, "DT_GroupTotal": true, "DT_GrandTotal": true},
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("No rows", func(t *testing.T) {
+		if result := appendGroupTotals(nil, "account", []string{"debit"}); len(result) != 0 {
+			t.Errorf("Expected no rows, got %v", result)
+		}
+	})
+}
+
+type TestLedgerEntry struct {
+	ID      int    `json:"id"`
+	Account string `json:"account"`
+	Amount  int    `json:"amount"`
+}
+
+func TestOfReturnGroupTotals(t *testing.T) {
+	fake := &fakeDB{
+		count: 3,
+		result: fakeResult{
+			columns: []string{"id", "account", "amount"},
+			rows: [][]driver.Value{
+				{int64(1), "bank", int64(100)},
+				{int64(2), "cash", int64(20)},
+				{int64(3), "cash", int64(30)},
+			},
+		},
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("")
+
+	var entries []TestLedgerEntry
+	opts := NewOptions().WithDefaultOrder("account ASC").WithGroupTotals("account", "amount")
+	result, err := OfReturn(c, db.Model(&TestLedgerEntry{}), &entries, nil, nil, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if len(rows) != 6 {
+		t.Fatalf("Expected 3 rows and 3 total rows, got %v", rows)
+	}
+	if rows[1]["DT_GroupTotal"] != true || rows[1]["amount"] != int64(100) {
+		t.Errorf("Expected bank subtotal, got %v", rows[1])
+	}
+	if rows[4]["account"] != "cash" || rows[4]["amount"] != int64(50) {
+		t.Errorf("Expected cash subtotal, got %v", rows[4])
+	}
+	if rows[5]["DT_GrandTotal"] != true || rows[5]["amount"] != int64(150) {
+		t.Errorf("Expected grand total, got %v", rows[5])
+	}
+	if result.RecordsTotal != 3 || result.RecordsFiltered != 3 {
+		t.Errorf("Expected counts to exclude total rows, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+	}
+}
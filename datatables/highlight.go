@@ -0,0 +1,106 @@
+package datatables
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// matchesKey is the row key under which search match metadata is stored
+const matchesKey = "DT_Matches"
+
+// MatchPosition describes a single occurrence of the search term within a cell value.
+// Positions are expressed in runes (characters), not bytes.
+type MatchPosition struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// annotateMatches adds the DT_Matches key to every row, describing which
+// searchable columns contain the search term and where.
+//
+// Matching is case-insensitive, mirroring the LOWER(...) LIKE LOWER(?) search
+// performed at the SQL level. Searchable columns written in table.column
+// notation are resolved against the row using the column part only.
+//
+// Rows are modified in place.
+func annotateMatches(rows []map[string]interface{}, searchable []string, term string) {
+	needle := lowerRunes(term)
+	if len(needle) == 0 {
+		return
+	}
+
+	for _, row := range rows {
+		matches := make(map[string][]MatchPosition)
+
+		for _, col := range searchable {
+			key := rowKeyForColumn(row, col)
+			if key == "" {
+				continue
+			}
+
+			val := row[key]
+			if val == nil {
+				continue
+			}
+
+			if positions := findMatches(lowerRunes(fmt.Sprint(val)), needle); len(positions) > 0 {
+				matches[key] = positions
+			}
+		}
+
+		row[matchesKey] = matches
+	}
+}
+
+// rowKeyForColumn resolves a searchable column name to the matching row key.
+// Returns an empty string if the row does not contain the column.
+func rowKeyForColumn(row map[string]interface{}, col string) string {
+	if _, ok := row[col]; ok {
+		return col
+	}
+
+	// Fallback: strip the table prefix (e.g., "users.name" -> "name")
+	if idx := strings.LastIndex(col, "."); idx >= 0 {
+		if _, ok := row[col[idx+1:]]; ok {
+			return col[idx+1:]
+		}
+	}
+
+	return ""
+}
+
+// findMatches returns every non-overlapping occurrence of needle in haystack.
+func findMatches(haystack, needle []rune) []MatchPosition {
+	var positions []MatchPosition
+
+	for i := 0; i+len(needle) <= len(haystack); {
+		if runesEqual(haystack[i:i+len(needle)], needle) {
+			positions = append(positions, MatchPosition{Start: i, Length: len(needle)})
+			i += len(needle)
+			continue
+		}
+		i++
+	}
+
+	return positions
+}
+
+// lowerRunes lowercases a string rune by rune so that rune offsets
+// remain aligned with the original value.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package datatables
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotateMatches(t *testing.T) {
+	t.Run("Multi-column match", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"id": 1, "name": "Ann Annabel", "email": "ann@example.com"},
+		}
+
+		annotateMatches(rows, []string{"name", "users.email"}, "ANN")
+
+		matches, ok := rows[0][matchesKey].(map[string][]MatchPosition)
+		if !ok {
+			t.Fatalf("Expected %s to be set, got %v", matchesKey, rows[0][matchesKey])
+		}
+
+		expectedName := []MatchPosition{{Start: 0, Length: 3}, {Start: 4, Length: 3}}
+		if !reflect.DeepEqual(matches["name"], expectedName) {
+			t.Errorf("Expected name matches %v, got %v", expectedName, matches["name"])
+		}
+
+		expectedEmail := []MatchPosition{{Start: 0, Length: 3}}
+		if !reflect.DeepEqual(matches["email"], expectedEmail) {
+			t.Errorf("Expected email matches %v, got %v", expectedEmail, matches["email"])
+		}
+	})
+
+	t.Run("Positions are counted in runes", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"name": "José Jo"},
+		}
+
+		annotateMatches(rows, []string{"name"}, "jo")
+
+		matches := rows[0][matchesKey].(map[string][]MatchPosition)
+		expected := []MatchPosition{{Start: 0, Length: 2}, {Start: 5, Length: 2}}
+		if !reflect.DeepEqual(matches["name"], expected) {
+			t.Errorf("Expected %v, got %v", expected, matches["name"])
+		}
+	})
+
+	t.Run("Non-matching and missing columns are skipped", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"name": "John", "email": nil},
+		}
+
+		annotateMatches(rows, []string{"name", "email", "phone"}, "xyz")
+
+		matches := rows[0][matchesKey].(map[string][]MatchPosition)
+		if len(matches) != 0 {
+			t.Errorf("Expected no matches, got %v", matches)
+		}
+	})
+}
+
+func TestOptionsWithMatchHighlight(t *testing.T) {
+	opts := NewOptions().WithMatchHighlight(true)
+
+	if !opts.MatchHighlight {
+		t.Error("Expected MatchHighlight=true")
+	}
+}
@@ -0,0 +1,77 @@
+package datatables
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// HTTPHandler adapts the DataTables processing to the standard library, for
+// projects that do not use Gin. Each request is parsed from the
+// *http.Request (query string, form, or JSON body, as in OfReturn), processed
+// with the same searching, ordering, pagination, and transformations, and
+// written as the bare DataTables JSON expected by the jQuery plugin.
+//
+// Errors are written in the JSONError format: a ValidationError responds
+// with 400 Bad Request, other errors with 500 Internal Server Error.
+// Options that receive the Gin context (such as WithColumnACL) get a context
+// wrapping the request, so only its request accessors (c.Request, c.Query,
+// c.GetHeader) are meaningful.
+//
+// Parameters:
+//   - db: GORM query builder, run with the request's context (defaults to T's table)
+//   - searchable: List of columns that support global search
+//   - orderable: Mapping between frontend column names and database columns
+//   - opts: Column customizations, as for OfReturn
+//
+// Example:
+//   http.Handle("/api/users", datatables.HTTPHandler[User](
+//       db.Model(&User{}),
+//       []string{"name", "email"},
+//       map[string]string{"name": "name", "created": "created_at"},
+//       datatables.NewOptions(),
+//   ))
+func HTTPHandler[T any](db *gorm.DB, searchable []string, orderable map[string]string, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c := &gin.Context{Request: r}
+
+		query := db.WithContext(r.Context())
+		if query.Statement.Model == nil && query.Statement.Table == "" {
+			// Count and fetch from T's table unless the query names one
+			query = query.Model(new(T))
+		}
+
+		var dest []T
+		result, err := OfReturn(c, query, &dest, searchable, orderable, opts)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				status = http.StatusBadRequest
+			}
+			writeHTTPJSON(w, status, opts.PureJSON, dto.SuccessResponse{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+				Errors:  err.Error(),
+			})
+			return
+		}
+
+		writeHTTPJSON(w, http.StatusOK, opts.PureJSON, result)
+	}
+}
+
+// writeHTTPJSON writes a JSON response with the given status code,
+// leaving HTML characters unescaped when pure is set.
+func writeHTTPJSON(w http.ResponseWriter, status int, pure bool, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(!pure)
+	_ = enc.Encode(body)
+}
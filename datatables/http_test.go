@@ -0,0 +1,91 @@
+package datatables
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	t.Run("Query string request", func(t *testing.T) {
+		fake := &fakeDB{count: 2, result: userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"})}
+		db := newFakeGormDB(t, fake)
+		handler := HTTPHandler[TestUser](db.Model(&TestUser{}), []string{"name"}, map[string]string{"name": "name"}, NewOptions().Remove("email"))
+
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/users?draw=3&start=0&length=1&search[value]=jo&order[0][column]=name&order[0][dir]=desc", nil))
+
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			t.Fatalf("Expected a 200 JSON response, got %d %q", w.Code, w.Header().Get("Content-Type"))
+		}
+
+		var result struct {
+			Draw            int64                    `json:"draw"`
+			RecordsTotal    int64                    `json:"recordsTotal"`
+			RecordsFiltered int64                    `json:"recordsFiltered"`
+			Data            []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if result.Draw != 3 || result.RecordsTotal != 2 || len(result.Data) != 1 {
+			t.Errorf("Unexpected response: %s", w.Body.String())
+		}
+		if result.Data[0]["name"] != "John" || result.Data[0]["DT_RowIndex"] != float64(1) {
+			t.Errorf("Expected a transformed row, got %v", result.Data[0])
+		}
+		if _, exists := result.Data[0]["email"]; exists {
+			t.Errorf("Expected email to be removed, got %v", result.Data[0])
+		}
+
+		expected := "SELECT * FROM `test_users` WHERE LOWER(name) LIKE LOWER(?) ORDER BY name desc LIMIT ?"
+		if last := fake.LastSelect(); last.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+		}
+	})
+
+	t.Run("Form and JSON bodies", func(t *testing.T) {
+		requests := map[string]*http.Request{
+			"form": httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(url.Values{"draw": {"5"}, "length": {"1"}}.Encode())),
+			"json": httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"draw": 5, "length": 1}`)),
+		}
+		requests["form"].Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		requests["json"].Header.Set("Content-Type", "application/json")
+
+		for name, r := range requests {
+			t.Run(name, func(t *testing.T) {
+				fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+				db := newFakeGormDB(t, fake)
+
+				w := httptest.NewRecorder()
+				HTTPHandler[TestUser](db, nil, nil, NewOptions())(w, r)
+
+				var result dto.Datatables
+				if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || result.Draw != 5 {
+					t.Errorf("Expected draw=5, got %s (err %v)", w.Body.String(), err)
+				}
+			})
+		}
+	})
+
+	t.Run("Validation errors respond with 400", func(t *testing.T) {
+		fake := &fakeDB{count: 1}
+		db := newFakeGormDB(t, fake)
+
+		w := httptest.NewRecorder()
+		HTTPHandler[TestUser](db, []string{"name; DROP TABLE users"}, nil, NewOptions())(w, httptest.NewRequest(http.MethodGet, "/?search[value]=x", nil))
+
+		var response dto.SuccessResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if w.Code != http.StatusBadRequest || response.Success || !strings.Contains(response.Message, "invalid characters") {
+			t.Errorf("Expected a 400 error response, got %d %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package datatables
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SelectIDs returns the id column values of every record matching the
+// DataTables request, across all pages. This supports "select all" bulk
+// actions without fetching full rows.
+//
+// The global search, per-column searches, declarative filters, HAVING
+// conditions, and ordering are applied exactly as in OfReturn (including the
+// ColumnACL), but pagination is not. The ids are fetched with a single Pluck query, so
+// WithInMemorySearch is not supported here and searches run in SQL.
+//
+// Parameters:
+//   - c: Gin context containing request parameters
+//   - query: GORM query builder (must have a Model or Table)
+//   - searchable: Columns that support global search
+//   - orderable: Map of frontend column names to database columns
+//   - opts: Configuration options
+//   - idColumn: The column to return, validated like other column names
+//
+// Example:
+//   ids, err := datatables.SelectIDs(c, db.Model(&User{}), searchable, orderable, opts, "id")
+func SelectIDs(
+	c *gin.Context,
+	query *gorm.DB,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+	idColumn string,
+) ([]interface{}, error) {
+	return selectIDs(c, query, searchable, orderable, opts, idColumn, nil)
+}
+
+// SelectIDsExcluding returns the effective selection of a "select all with
+// exclusions" UI: the id column values of every record matching the
+// DataTables request, minus the excluded ids. The exclusion is applied in SQL
+// ("id NOT IN (...)"), so only the selected ids are fetched.
+//
+// At most 1000 excluded ids are accepted (a ValidationError is returned
+// otherwise), since very large NOT IN lists are slow or rejected by databases.
+//
+// Example:
+//   ids, err := datatables.SelectIDsExcluding(c, db.Model(&User{}), searchable, orderable, opts, "id", body.Excluded)
+func SelectIDsExcluding(
+	c *gin.Context,
+	query *gorm.DB,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+	idColumn string,
+	excluded []interface{},
+) ([]interface{}, error) {
+	if len(excluded) > maxExcludedIDs {
+		return nil, &ValidationError{
+			Field:   idColumn,
+			Message: "too many excluded ids: at most " + strconv.Itoa(maxExcludedIDs) + " are allowed",
+		}
+	}
+	return selectIDs(c, query, searchable, orderable, opts, idColumn, excluded)
+}
+
+// maxExcludedIDs is the maximum size of the NOT IN list built by SelectIDsExcluding
+const maxExcludedIDs = 1000
+
+// selectIDs implements SelectIDs and SelectIDsExcluding.
+func selectIDs(
+	c *gin.Context,
+	query *gorm.DB,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+	idColumn string,
+	excluded []interface{},
+) ([]interface{}, error) {
+	if !isValidColumnName(idColumn) {
+		return nil, &ValidationError{
+			Field:   idColumn,
+			Message: "id column name contains invalid characters" + invalidColumnDetail(idColumn),
+		}
+	}
+
+	// Build the filtering like OfReturn; pagination does not apply, and the
+	// search always runs in SQL
+	params := parseParams(c, opts)
+	params.Start = 0
+	opts.InMemorySearch = false
+	req, err := resolveRequest(c, query, query.Statement.Model, params, searchable, orderable, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts = req.opts
+
+	if opts.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		query, cancel = withQueryTimeout(c, query, opts.QueryTimeout)
+		defer cancel()
+	}
+
+	// Group the search so its OR chain does not swallow the exclusion
+	filteredQuery, _ := req.filter(c, query, len(excluded) > 0)
+	if len(excluded) > 0 {
+		filteredQuery = filteredQuery.Where(idColumn+" NOT IN ?", excluded)
+	}
+	filteredQuery = applyOrdering(filteredQuery, req.params, req.orderable, opts)
+
+	ids := []interface{}{}
+	if err := withRetry(query.Statement.Context, opts, func() error {
+		return filteredQuery.Session(&gorm.Session{}).Pluck(idColumn, &ids).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
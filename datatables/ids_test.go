@@ -0,0 +1,185 @@
+package datatables
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSelectIDs(t *testing.T) {
+	fake := &fakeDB{
+		result: fakeResult{
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(3)}, {int64(1)}, {int64(2)}},
+		},
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("start=10&length=2&search[value]=jo&order[0][column]=name&order[0][dir]=desc")
+
+	ids, err := SelectIDs(c, db.Model(&TestUser{}), []string{"name"}, map[string]string{"name": "name"}, NewOptions(), "id")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []interface{}{int64(3), int64(1), int64(2)}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected ids %v, got %v", expected, ids)
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 1 {
+		t.Fatalf("Expected a single query, got %d", len(queries))
+	}
+
+	sql := queries[0].SQL
+	expectedSQL := "SELECT `id` FROM `test_users` WHERE LOWER(name) LIKE LOWER(?) ORDER BY name desc"
+	if sql != expectedSQL {
+		t.Errorf("Unexpected query:\n got  %s\n want %s", sql, expectedSQL)
+	}
+	if strings.Contains(sql, "LIMIT") || strings.Contains(sql, "OFFSET") {
+		t.Errorf("Expected no pagination, got %s", sql)
+	}
+}
+
+func TestSelectIDsEmpty(t *testing.T) {
+	fake := &fakeDB{result: fakeResult{columns: []string{"id"}}}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("")
+
+	ids, err := SelectIDs(c, db.Model(&TestUser{}), nil, nil, NewOptions(), "id")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ids == nil || len(ids) != 0 {
+		t.Errorf("Expected an empty non-nil slice, got %#v", ids)
+	}
+}
+
+func TestSelectIDsInvalidColumn(t *testing.T) {
+	db := newFakeGormDB(t, &fakeDB{})
+	c, _ := newTestContext("")
+
+	_, err := SelectIDs(c, db.Model(&TestUser{}), nil, nil, NewOptions(), "id; DROP TABLE users")
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+	}
+}
+
+func TestSelectIDsExcluding(t *testing.T) {
+	t.Run("Excluded ids are filtered in SQL", func(t *testing.T) {
+		fake := &fakeDB{
+			result: fakeResult{
+				columns: []string{"id"},
+				rows:    [][]driver.Value{{int64(1)}, {int64(4)}},
+			},
+		}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		ids, err := SelectIDsExcluding(c, db.Model(&TestUser{}), []string{"name", "email"}, nil, NewOptions(), "id", []interface{}{2, 3})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(ids, []interface{}{int64(1), int64(4)}) {
+			t.Errorf("Unexpected ids: %v", ids)
+		}
+
+		q := fake.Queries()[0]
+		expected := "SELECT `id` FROM `test_users` WHERE (LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)) AND id NOT IN (?,?)"
+		if q.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", q.SQL, expected)
+		}
+		if !reflect.DeepEqual(q.Args, []interface{}{"%jo%", "%jo%", int64(2), int64(3)}) {
+			t.Errorf("Unexpected args: %v", q.Args)
+		}
+	})
+
+	t.Run("No exclusions selects all", func(t *testing.T) {
+		fake := &fakeDB{result: fakeResult{columns: []string{"id"}}}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		if _, err := SelectIDsExcluding(c, db.Model(&TestUser{}), nil, nil, NewOptions(), "id", nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if sql := fake.Queries()[0].SQL; strings.Contains(sql, "NOT IN") {
+			t.Errorf("Expected no NOT IN clause, got %s", sql)
+		}
+	})
+
+	t.Run("Too many exclusions", func(t *testing.T) {
+		fake := &fakeDB{}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		excluded := make([]interface{}, maxExcludedIDs+1)
+		_, err := SelectIDsExcluding(c, db.Model(&TestUser{}), nil, nil, NewOptions(), "id", excluded)
+		if _, ok := err.(*ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+		if len(fake.Queries()) != 0 {
+			t.Errorf("Expected no queries, got %v", fake.Queries())
+		}
+	})
+
+	t.Run("Invalid id column", func(t *testing.T) {
+		db := newFakeGormDB(t, &fakeDB{})
+		c, _ := newTestContext("")
+
+		_, err := SelectIDsExcluding(c, db.Model(&TestUser{}), nil, nil, NewOptions(), "id)--", []interface{}{1})
+		if _, ok := err.(*ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+}
+
+func TestSelectIDsMatchesOfReturnFiltering(t *testing.T) {
+	hideEmail := func(c *gin.Context, column string) bool { return column != "email" }
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"ACL-hidden columns are not searched", NewOptions().WithColumnACL(hideEmail),
+			"SELECT `id` FROM `test_users` WHERE LOWER(name) LIKE LOWER(?)"},
+		{"Having", NewOptions().WithHaving("COUNT(*) > ?", 1),
+			"SELECT `id` FROM `test_users` WHERE LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)  HAVING COUNT(*) > ?"},
+		{"Deep offsets are not rejected", NewOptions().WithMaxOffset(5, true),
+			"SELECT `id` FROM `test_users` WHERE LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{result: fakeResult{columns: []string{"id"}}}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("start=10&search[value]=example.com")
+
+			if _, err := SelectIDs(c, db.Model(&TestUser{}), []string{"name", "email"}, nil, tt.opts, "id"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if sql := fake.LastSelect().SQL; sql != tt.expected {
+				t.Errorf("Unexpected query:\n got  %s\n want %s", sql, tt.expected)
+			}
+		})
+	}
+
+	t.Run("ACL-hidden columns are not searched with exclusions", func(t *testing.T) {
+		fake := &fakeDB{result: fakeResult{columns: []string{"id"}}}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=example.com")
+
+		opts := NewOptions().WithColumnACL(hideEmail)
+		if _, err := SelectIDsExcluding(c, db.Model(&TestUser{}), []string{"name", "email"}, nil, opts, "id", []interface{}{2}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "SELECT `id` FROM `test_users` WHERE LOWER(name) LIKE LOWER(?) AND id NOT IN (?)"
+		if sql := fake.LastSelect().SQL; sql != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", sql, expected)
+		}
+	})
+}
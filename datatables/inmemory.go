@@ -0,0 +1,124 @@
+package datatables
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// filterRows keeps only the rows where at least one searchable column
+// contains the search term (case-insensitive). With unaccent set, diacritics
+// are ignored too, so "jose" matches "José".
+//
+// Columns are looked up by their row key, with or without the table prefix
+// ("users.name" matches the "name" key); other keys are never matched, so
+// removed and ACL-hidden values cannot be probed. Strings, numbers, booleans,
+// and time.Time values are matched; nil values, collections, and nested
+// structs are skipped.
+func filterRows(rows []map[string]interface{}, searchable []string, term string, unaccent bool) []map[string]interface{} {
+	fold := searchFold(unaccent)
+	needle := fold(term)
+	out := make([]map[string]interface{}, 0, len(rows))
+
+	for _, row := range rows {
+		columns := make(map[string]interface{}, len(searchable))
+		for _, col := range searchable {
+			if key := rowKeyForColumn(row, col); key != "" {
+				columns[key] = row[key]
+			}
+		}
+		if rowContains(columns, needle, fold) {
+			out = append(out, row)
+		}
+	}
+
+	return out
+}
+
+// searchFold returns the folding applied to searched values and terms:
+// lowercasing, and removing diacritics too with unaccent set.
+func searchFold(unaccent bool) func(string) string {
+	if unaccent {
+		return func(s string) string { return strings.ToLower(removeDiacritics(s)) }
+	}
+	return strings.ToLower
+}
+
+// rowContains reports whether any stringifiable value of the row contains
+// the already folded needle.
+func rowContains(row map[string]interface{}, needle string, fold func(string) string) bool {
+	for _, val := range row {
+		s, ok := stringifyValue(val)
+		if ok && strings.Contains(fold(s), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeDiacritics strips combining marks from the decomposed form of s
+// ("José" -> "Jose").
+func removeDiacritics(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// stringifyValue returns the string form of a scalar value.
+// Returns false for values that cannot be meaningfully matched as text.
+func stringifyValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case time.Time:
+		return v.Format(time.RFC3339), true
+	case *time.Time:
+		if v == nil {
+			return "", false
+		}
+		return v.Format(time.RFC3339), true
+	case fmt.Stringer:
+		return v.String(), true
+	}
+
+	if isNumeric(value) {
+		return fmt.Sprint(value), true
+	}
+	if b, ok := value.(bool); ok {
+		return fmt.Sprint(b), true
+	}
+	return "", false
+}
+
+// paginateRows returns the page of rows described by start and length.
+// A length of 0 returns no rows, and -1 (or any negative value) returns all rows from start.
+func paginateRows(rows []map[string]interface{}, start, length int) []map[string]interface{} {
+	if length == 0 {
+		return []map[string]interface{}{}
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(rows) {
+		return []map[string]interface{}{}
+	}
+
+	end := len(rows)
+	if length > 0 && start+length < end {
+		end = start + length
+	}
+
+	return rows[start:end]
+}
@@ -0,0 +1,251 @@
+package datatables
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFilterRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "John Doe", "active": true},
+		{"id": 2, "name": "Jane Roe", "active": false},
+		{"id": 12, "name": "Bob", "joined": time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"id": 3, "name": nil, "tags": []string{"doe"}},
+	}
+
+	tests := []struct {
+		term     string
+		expected []interface{}
+	}{
+		{"DOE", []interface{}{1}},
+		{"roe", []interface{}{2}},
+		{"2", []interface{}{2, 12}},
+		{"false", []interface{}{2}},
+		{"2024-01", []interface{}{12}},
+		{"nothing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			result := filterRows(rows, []string{"id", "name", "active", "joined", "tags"}, tt.term, false)
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d rows, got %d: %v", len(tt.expected), len(result), result)
+			}
+			for i, id := range tt.expected {
+				if result[i]["id"] != id {
+					t.Errorf("Expected row %d to have id=%v, got %v", i, id, result[i]["id"])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterRowsSearchableColumns(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "John", "password": "secret"},
+		{"id": 2, "name": "Secretary", "password": "hunter2"},
+	}
+
+	tests := []struct {
+		name       string
+		searchable []string
+		expected   []interface{}
+	}{
+		{"Only searchable keys are matched", []string{"name"}, []interface{}{2}},
+		{"Table-prefixed columns match their key", []string{"users.name"}, []interface{}{2}},
+		{"Unknown columns match nothing", []string{"email"}, nil},
+		{"No searchable columns match nothing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := filterRows(rows, tt.searchable, "secret", false)
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d rows, got %d: %v", len(tt.expected), len(result), result)
+			}
+			for i, id := range tt.expected {
+				if result[i]["id"] != id {
+					t.Errorf("Expected row %d to have id=%v, got %v", i, id, result[i]["id"])
+				}
+			}
+		})
+	}
+}
+
+func TestPaginateRows(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1}, {"id": 2}, {"id": 3}}
+
+	tests := []struct {
+		name          string
+		start, length int
+		expected      int
+	}{
+		{"First page", 0, 2, 2},
+		{"Last partial page", 2, 2, 1},
+		{"All rows", 0, -1, 3},
+		{"Zero length", 0, 0, 0},
+		{"Start past end", 5, 2, 0},
+		{"Negative start", -1, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := paginateRows(rows, tt.start, tt.length)
+			if len(result) != tt.expected {
+				t.Errorf("Expected %d rows, got %d", tt.expected, len(result))
+			}
+		})
+	}
+}
+
+func TestOfReturnInMemorySearch(t *testing.T) {
+	fake := &fakeDB{
+		count: 3,
+		result: userResult(
+			TestUser{ID: 1, Name: "John", Email: "john@example.com"},
+			TestUser{ID: 2, Name: "Jane", Email: "jane@example.com"},
+			TestUser{ID: 3, Name: "Johnny", Email: "johnny@example.com"},
+		),
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("search[value]=john&start=1&length=1")
+
+	var users []TestUser
+	result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithInMemorySearch(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.RecordsTotal != 3 || result.RecordsFiltered != 2 {
+		t.Errorf("Expected total=3 filtered=2, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if len(rows) != 1 || rows[0]["name"] != "Johnny" || rows[0]["DT_RowIndex"] != 2 {
+		t.Errorf("Expected second page with Johnny, got %v", rows)
+	}
+
+	if fake.CountQueries() != 1 {
+		t.Errorf("Expected only the total count query, got %d", fake.CountQueries())
+	}
+	if sql := fake.LastSelect().SQL; strings.Contains(sql, "LIKE") || strings.Contains(sql, "LIMIT") {
+		t.Errorf("Expected full unfiltered fetch, got %s", sql)
+	}
+}
+
+func TestOfReturnInMemorySearchHiddenColumns(t *testing.T) {
+	users := []TestUser{
+		{ID: 1, Name: "John", Email: "john@example.com"},
+		{ID: 2, Name: "Jane", Email: "jane@example.com"},
+	}
+
+	tests := []struct {
+		name       string
+		searchable []string
+		opts       Options
+	}{
+		{"Removed columns", []string{"name"}, NewOptions().Remove("email")},
+		{"ACL-hidden columns", []string{"name", "email"}, NewOptions().WithColumnACL(func(c *gin.Context, column string) bool { return column != "email" })},
+		{"No searchable columns", nil, NewOptions()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 2, result: userResult(users...)}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("search[value]=example.com")
+
+			var dest []TestUser
+			result, err := OfReturn(c, db.Model(&TestUser{}), &dest, tt.searchable, nil, tt.opts.WithInMemorySearch(true))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			wantFiltered := int64(0)
+			if tt.searchable == nil {
+				// The search is ignored, as in SQL
+				wantFiltered = 2
+			}
+			if result.RecordsFiltered != wantFiltered {
+				t.Errorf("Expected %d filtered records, got %d: %v", wantFiltered, result.RecordsFiltered, result.Data)
+			}
+		})
+	}
+}
+
+func TestFilterRowsUnaccent(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "José Núñez"},
+		{"id": 2, "name": "Zoë"},
+		{"id": 3, "name": "Jose Smith"},
+	}
+
+	tests := []struct {
+		term     string
+		unaccent bool
+		expected []interface{}
+	}{
+		{"jose", true, []interface{}{1, 3}},
+		{"jose", false, []interface{}{3}},
+		{"JOSÉ", true, []interface{}{1, 3}},
+		{"nunez", true, []interface{}{1}},
+		{"zoe", true, []interface{}{2}},
+		{"zoe", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			result := filterRows(rows, []string{"name"}, tt.term, tt.unaccent)
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d rows, got %d: %v", len(tt.expected), len(result), result)
+			}
+			for i, id := range tt.expected {
+				if result[i]["id"] != id {
+					t.Errorf("Expected row %d to have id=%v, got %v", i, id, result[i]["id"])
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveDiacritics(t *testing.T) {
+	tests := map[string]string{
+		"José":       "Jose",
+		"Ångström":   "Angstrom",
+		"façade":     "facade",
+		"plain text": "plain text",
+	}
+
+	for input, expected := range tests {
+		if result := removeDiacritics(input); result != expected {
+			t.Errorf("removeDiacritics(%q) = %q, want %q", input, result, expected)
+		}
+	}
+}
+
+func TestOfReturnInMemoryUnaccentSearch(t *testing.T) {
+	fake := &fakeDB{
+		count:  2,
+		result: userResult(TestUser{ID: 1, Name: "José"}, TestUser{ID: 2, Name: "Jane"}),
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("search[value]=jose")
+
+	var users []TestUser
+	opts := NewOptions().WithInMemorySearch(true).WithUnaccentSearch(true)
+	result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if result.RecordsFiltered != 1 || len(rows) != 1 || rows[0]["name"] != "José" {
+		t.Errorf("Expected José to match, got %d filtered: %v", result.RecordsFiltered, rows)
+	}
+}
@@ -0,0 +1,98 @@
+package datatables
+
+import (
+	"strings"
+	"unicode"
+)
+
+// KeyCase selects how output row keys are cased.
+type KeyCase int
+
+const (
+	// PreserveCase keeps keys as produced by the converter and callbacks
+	PreserveCase KeyCase = iota
+
+	// SnakeCase converts keys to snake_case (e.g., "ProductID" -> "product_id")
+	SnakeCase
+
+	// CamelCase converts keys to camelCase (e.g., "ProductID" -> "productId")
+	CamelCase
+)
+
+// applyKeyCase renames the keys of every row to the given case.
+// DataTables' reserved "DT_" keys and the index column are kept as-is,
+// since the client looks them up by their exact names.
+//
+// Rows are modified in place.
+func applyKeyCase(rows []map[string]interface{}, keyCase KeyCase, indexColumn string) {
+	if keyCase == PreserveCase {
+		return
+	}
+
+	for i, row := range rows {
+		renamed := make(map[string]interface{}, len(row))
+		for k, v := range row {
+			if k != indexColumn && !strings.HasPrefix(k, "DT_") {
+				k = convertKeyCase(k, keyCase)
+			}
+			renamed[k] = v
+		}
+		rows[i] = renamed
+	}
+}
+
+// convertKeyCase converts a single key to the given case.
+func convertKeyCase(key string, keyCase KeyCase) string {
+	words := splitWords(key)
+
+	switch keyCase {
+	case SnakeCase:
+		return strings.Join(words, "_")
+	case CamelCase:
+		for i := 1; i < len(words); i++ {
+			r := []rune(words[i])
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+		return strings.Join(words, "")
+	default:
+		return key
+	}
+}
+
+// splitWords splits a snake_case, kebab-case, camelCase or PascalCase key into
+// lowercase words. Runs of capitals are treated as an acronym
+// (e.g., "HTTPServerID" -> ["http", "server", "id"]).
+func splitWords(key string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	if len(words) == 0 {
+		return []string{key}
+	}
+	return words
+}
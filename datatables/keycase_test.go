@@ -0,0 +1,70 @@
+package datatables
+
+import (
+	"testing"
+)
+
+func TestConvertKeyCase(t *testing.T) {
+	tests := []struct {
+		key   string
+		snake string
+		camel string
+	}{
+		{"ProductID", "product_id", "productId"},
+		{"Title", "title", "title"},
+		{"product_id", "product_id", "productId"},
+		{"productId", "product_id", "productId"},
+		{"HTTPServerURL", "http_server_url", "httpServerUrl"},
+		{"created_at", "created_at", "createdAt"},
+		{"Address2Line", "address2_line", "address2Line"},
+		{"name", "name", "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := convertKeyCase(tt.key, SnakeCase); got != tt.snake {
+				t.Errorf("convertKeyCase(%q, SnakeCase) = %q, want %q", tt.key, got, tt.snake)
+			}
+			if got := convertKeyCase(tt.key, CamelCase); got != tt.camel {
+				t.Errorf("convertKeyCase(%q, CamelCase) = %q, want %q", tt.key, got, tt.camel)
+			}
+		})
+	}
+}
+
+func TestApplyKeyCase(t *testing.T) {
+	type product struct {
+		ProductID int
+		Title     string
+		UnitPrice float64 `json:"unit_price"`
+	}
+
+	rows := structToMapSlice(&[]product{{ProductID: 7, Title: "Book", UnitPrice: 9.5}})
+	opts := NewOptions().Add("DisplayName", func(row map[string]interface{}) interface{} {
+		return row["Title"]
+	})
+	rows = applyOptions(nil, rows, opts, 0)
+
+	applyKeyCase(rows, CamelCase, opts.IndexColumn)
+
+	expected := map[string]interface{}{
+		"productId":   7,
+		"title":       "Book",
+		"unitPrice":   9.5,
+		"displayName": "Book",
+		"DT_RowIndex": 1,
+	}
+	if len(rows[0]) != len(expected) {
+		t.Fatalf("Expected keys %v, got %v", expected, rows[0])
+	}
+	for k, v := range expected {
+		if rows[0][k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, rows[0][k])
+		}
+	}
+
+	applyKeyCase(rows, SnakeCase, opts.IndexColumn)
+	if rows[0]["product_id"] != 7 || rows[0]["display_name"] != "Book" {
+		t.Errorf("Expected snake_case keys, got %v", rows[0])
+	}
+}
@@ -0,0 +1,59 @@
+package datatables
+
+import (
+	"sort"
+	"strings"
+)
+
+// Logger receives diagnostic warnings about suspicious configurations.
+// It is satisfied by *log.Logger and most logging libraries' sugared loggers.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// warnf logs a warning through opts.Logger, if one is set.
+func warnf(opts Options, format string, v ...interface{}) {
+	if opts.Logger != nil {
+		opts.Logger.Printf("datatables: warning: "+format, v...)
+	}
+}
+
+// removedOrderWarnings describes every DefaultOrder column and orderable
+// mapping that targets a column removed from the output. Ordering by such a
+// column still works, since removal is display-only, but it is usually a
+// configuration mistake. Database columns written in table.column notation
+// are compared using the column part only.
+func removedOrderWarnings(orderable map[string]string, opts Options) []string {
+	removed := append(append(globalRemoveColumns(), opts.RemoveColumns...), opts.SearchableHidden...)
+	if len(removed) == 0 {
+		return nil
+	}
+
+	isRemoved := func(col string) bool {
+		if idx := strings.LastIndex(col, "."); idx >= 0 {
+			col = col[idx+1:]
+		}
+		return containsString(removed, col)
+	}
+
+	var warnings []string
+	for _, col := range orderColumns(opts.DefaultOrder) {
+		if isRemoved(col) {
+			warnings = append(warnings, "default order column \""+col+"\" is removed from the output")
+		}
+	}
+
+	keys := make([]string, 0, len(orderable))
+	for key := range orderable {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if isRemoved(key) || isRemoved(orderable[key]) {
+			warnings = append(warnings, "orderable column \""+key+"\" ("+orderable[key]+") is removed from the output")
+		}
+	}
+
+	return warnings
+}
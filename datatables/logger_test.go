@@ -0,0 +1,194 @@
+package datatables
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingLogger collects logged messages for assertions.
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.messages...)
+}
+
+func TestRemovedOrderWarnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		orderable map[string]string
+		opts      Options
+		expected  []string
+	}{
+		{
+			name:     "No removed columns",
+			opts:     NewOptions().WithDefaultOrder("created_at DESC"),
+			expected: nil,
+		},
+		{
+			name: "Default order on a removed column",
+			opts: NewOptions().WithDefaultOrder("users.created_at DESC, id ASC").Remove("created_at"),
+			expected: []string{
+				`default order column "users.created_at" is removed from the output`,
+			},
+		},
+		{
+			name:      "Orderable key or value removed",
+			orderable: map[string]string{"name": "name", "created": "created_at", "secret": "users.secret"},
+			opts:      NewOptions().Remove("created_at").WithSearchableHidden("secret"),
+			expected: []string{
+				`orderable column "created" (created_at) is removed from the output`,
+				`orderable column "secret" (users.secret) is removed from the output`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := removedOrderWarnings(tt.orderable, tt.opts)
+			if !reflect.DeepEqual(warnings, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, warnings)
+			}
+		})
+	}
+}
+
+func TestOfReturnLogsRemovedOrderColumns(t *testing.T) {
+	fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("")
+
+	logger := &recordingLogger{}
+	opts := NewOptions().WithDefaultOrder("email DESC").Remove("email").WithLogger(logger)
+
+	var users []TestUser
+	result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages := logger.Messages()
+	if len(messages) != 1 || !strings.Contains(messages[0], `default order column "email"`) {
+		t.Errorf("Expected one default order warning, got %v", messages)
+	}
+	if !strings.Contains(fake.LastSelect().SQL, "ORDER BY email DESC") {
+		t.Errorf("Expected ordering to still apply, got %s", fake.LastSelect().SQL)
+	}
+	if len(result.Data.([]map[string]interface{})) != 1 {
+		t.Errorf("Expected the request to succeed, got %v", result.Data)
+	}
+}
+
+func TestOfReturnCountMismatch(t *testing.T) {
+	// Simulate a join fan-out: the filtered count exceeds the total
+	newDB := func() *fakeDB {
+		fake := &fakeDB{}
+		fake.handler = func(_ context.Context, query string, _ []interface{}) (fakeResult, error) {
+			if !isCountQuery(query) {
+				return userResult(TestUser{ID: 1, Name: "John"}), nil
+			}
+			count := int64(3)
+			if strings.Contains(query, "LIKE") {
+				count = 5
+			}
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{count}}}, nil
+		}
+		return fake
+	}
+
+	t.Run("Logged by default", func(t *testing.T) {
+		db := newFakeGormDB(t, newDB())
+		c, _ := newTestContext("search[value]=jo")
+
+		logger := &recordingLogger{}
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithLogger(logger))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.RecordsTotal != 3 || result.RecordsFiltered != 5 {
+			t.Errorf("Expected counts 3/5, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+		}
+
+		messages := logger.Messages()
+		if len(messages) != 1 || !strings.Contains(messages[0], "recordsFiltered=5, recordsTotal=3") {
+			t.Errorf("Expected a count mismatch warning, got %v", messages)
+		}
+	})
+
+	t.Run("Error in strict mode", func(t *testing.T) {
+		db := newFakeGormDB(t, newDB())
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithStrictCounts(true))
+		if !errors.Is(err, ErrCountMismatch) {
+			t.Fatalf("Expected ErrCountMismatch, got %v", err)
+		}
+
+		var mismatch *CountMismatchError
+		if !errors.As(err, &mismatch) || mismatch.Total != 3 || mismatch.Filtered != 5 {
+			t.Errorf("Unexpected mismatch details: %+v", mismatch)
+		}
+	})
+
+	t.Run("Consistent counts pass in strict mode", func(t *testing.T) {
+		fake := &fakeDB{count: 2, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithStrictCounts(true)); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestOfReturnSearchWithoutSearchableWarning(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		searchable []string
+		warns      bool
+	}{
+		{"Search without searchable columns", "search[value]=john", nil, true},
+		{"Search with searchable columns", "search[value]=john", []string{"name"}, false},
+		{"No search value", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext(tt.query)
+
+			logger := &recordingLogger{}
+			var users []TestUser
+			if _, err := OfReturn(c, db.Model(&TestUser{}), &users, tt.searchable, nil, NewOptions().WithLogger(logger)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			messages := logger.Messages()
+			warned := len(messages) == 1 && strings.Contains(messages[0], "no searchable columns are configured")
+			if warned != tt.warns || (!tt.warns && len(messages) != 0) {
+				t.Errorf("Expected warning=%v, got %v", tt.warns, messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package datatables
+
+import "reflect"
+
+// Merge combines two Options into a new, independent Options value.
+//
+// Merge rules:
+//   - Maps (e.g. AddColumns, EditColumns): union, with other winning on duplicate keys
+//   - Slices (e.g. RemoveColumns): union, keeping o's entries first and skipping duplicates
+//   - Scalars, functions, and other fields (e.g. IndexColumn, DefaultOrder):
+//     other wins when its value is non-zero
+//
+// Because boolean fields are scalars, a false value in other never disables
+// a feature enabled in o. Neither o nor other is modified: maps and slices are
+// copied, so the result can be customized further without affecting its inputs.
+//
+// Example:
+//   base := datatables.NewOptions().Remove("password").WithDefaultOrder("id DESC")
+//   opts := base.Merge(datatables.NewOptions().Remove("deleted_at").WithIndex("no", true))
+//   // RemoveColumns: ["password", "deleted_at"], DefaultOrder: "id DESC", IndexColumn: "no"
+func (o Options) Merge(other Options) Options {
+	result := reflect.New(reflect.TypeOf(o)).Elem()
+	base := reflect.ValueOf(o)
+	over := reflect.ValueOf(other)
+
+	for i := 0; i < base.NumField(); i++ {
+		result.Field(i).Set(mergeField(base.Field(i), over.Field(i)))
+	}
+
+	return result.Interface().(Options)
+}
+
+// mergeField merges a single Options field according to the Merge rules.
+func mergeField(base, over reflect.Value) reflect.Value {
+	switch base.Kind() {
+	case reflect.Map:
+		if base.IsNil() && over.IsNil() {
+			return base
+		}
+		merged := reflect.MakeMapWithSize(base.Type(), base.Len()+over.Len())
+		for _, m := range []reflect.Value{base, over} {
+			iter := m.MapRange()
+			for iter.Next() {
+				merged.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		return merged
+
+	case reflect.Slice:
+		if base.IsNil() && over.IsNil() {
+			return base
+		}
+		merged := reflect.MakeSlice(base.Type(), 0, base.Len()+over.Len())
+		for _, s := range []reflect.Value{base, over} {
+			for i := 0; i < s.Len(); i++ {
+				if !sliceContains(merged, s.Index(i)) {
+					merged = reflect.Append(merged, s.Index(i))
+				}
+			}
+		}
+		return merged
+	}
+
+	if !over.IsZero() {
+		return over
+	}
+	return base
+}
+
+// sliceContains reports whether the slice contains an element equal to v.
+// Non-comparable elements are never considered duplicates.
+func sliceContains(s, v reflect.Value) bool {
+	if !v.Type().Comparable() {
+		return false
+	}
+	for i := 0; i < s.Len(); i++ {
+		if s.Index(i).Interface() == v.Interface() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package datatables
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptionsMerge(t *testing.T) {
+	identity := func(value interface{}, row map[string]interface{}) interface{} { return value }
+	constant := func(v string) func(row map[string]interface{}) interface{} {
+		return func(row map[string]interface{}) interface{} { return v }
+	}
+
+	base := NewOptions().
+		WithDefaultOrder("id DESC").
+		WithQueryTimeout(time.Second).
+		WithMatchHighlight(true).
+		Add("badge", constant("base")).
+		Add("actions", constant("base")).
+		Edit("email", identity).
+		Remove("password", "token")
+
+	other := Options{
+		IndexColumn: "row_num",
+		ResetIndex:  true,
+		AddColumns:  map[string]func(row map[string]interface{}) interface{}{"badge": constant("other")},
+		EditColumns: map[string]func(value interface{}, row map[string]interface{}) interface{}{"name": identity},
+	}.Remove("token", "deleted_at")
+
+	merged := base.Merge(other)
+
+	t.Run("Other wins on non-zero scalars", func(t *testing.T) {
+		if merged.IndexColumn != "row_num" || !merged.ResetIndex {
+			t.Errorf("Expected index settings from other, got %q %v", merged.IndexColumn, merged.ResetIndex)
+		}
+		if merged.DefaultOrder != "id DESC" || merged.QueryTimeout != time.Second || !merged.MatchHighlight {
+			t.Errorf("Expected zero-valued fields in other to keep base values, got %+v", merged)
+		}
+	})
+
+	t.Run("Columns are combined", func(t *testing.T) {
+		if len(merged.AddColumns) != 2 || merged.AddColumns["badge"](nil) != "other" {
+			t.Errorf("Expected Add union with other winning, got %d columns", len(merged.AddColumns))
+		}
+		if len(merged.EditColumns) != 2 {
+			t.Errorf("Expected 2 edit columns, got %d", len(merged.EditColumns))
+		}
+
+		expected := []string{"password", "token", "deleted_at"}
+		if !reflect.DeepEqual(merged.RemoveColumns, expected) {
+			t.Errorf("Expected RemoveColumns %v, got %v", expected, merged.RemoveColumns)
+		}
+	})
+
+	t.Run("Result is independent", func(t *testing.T) {
+		merged.AddColumns["extra"] = constant("x")
+		merged.RemoveColumns[0] = "changed"
+
+		if _, exists := base.AddColumns["extra"]; exists {
+			t.Error("Base AddColumns should not be modified")
+		}
+		if _, exists := other.AddColumns["extra"]; exists {
+			t.Error("Other AddColumns should not be modified")
+		}
+		if base.RemoveColumns[0] != "password" {
+			t.Error("Base RemoveColumns should not be modified")
+		}
+	})
+}
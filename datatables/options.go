@@ -1,5 +1,12 @@
 package datatables
 
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
 // Options provides customization similar to Yajra DataTables.
 // It allows adding, editing, and removing columns dynamically,
 // as well as controlling the row index column and default ordering.
@@ -23,6 +30,280 @@ type Options struct {
 
 	// RemoveColumns is a list of columns to be removed from the final output
 	RemoveColumns []string
+
+	// MatchHighlight enables the DT_Matches annotation describing where the
+	// global search term matched within each returned row
+	MatchHighlight bool
+
+	// FlattenCollections converts slice and map field values into display-friendly
+	// strings (slices joined with CollectionSeparator, maps JSON-encoded)
+	FlattenCollections bool
+
+	// CollectionSeparator is the separator used to join slice values when
+	// FlattenCollections is enabled
+	CollectionSeparator string
+
+	// AutoSearchable searches all string columns of the model when no
+	// searchable columns are provided
+	AutoSearchable bool
+
+	// StableSortColumn is appended as a final ORDER BY term (typically the
+	// primary key) to guarantee stable pagination
+	StableSortColumn string
+
+	// PreserveNumericColumns lists columns whose numeric type must survive
+	// Edit callbacks so client-side numeric sorting keeps working
+	PreserveNumericColumns []string
+
+	// QueryTimeout bounds the duration of all database calls (zero means no timeout)
+	QueryTimeout time.Duration
+
+	// SearchNormalizers maps a searchable column to the string replacements
+	// applied to both the column (in SQL) and the search term before matching
+	SearchNormalizers map[string]map[string]string
+
+	// OrderingDisabled ignores any client-sent order, applying only DefaultOrder
+	OrderingDisabled bool
+
+	// InMemorySearch filters the fetched rows in Go instead of searching in SQL
+	InMemorySearch bool
+
+	// ExactColumns lists searchable columns matched with equality instead of LIKE
+	ExactColumns []string
+
+	// AbsoluteTotalModel, when set, makes recordsTotal count the whole model
+	// table instead of the base query (ignoring its WHERE clauses)
+	AbsoluteTotalModel interface{}
+
+	// RowIdColumn is the column whose value is used as the DT_RowId of each row
+	RowIdColumn string
+
+	// RowIdFunc computes the DT_RowId of each row; it takes precedence over RowIdColumn
+	RowIdFunc func(row map[string]interface{}) string
+
+	// MaxPageSizeFunc resolves the maximum page size per request
+	// (the default limit of 500 applies when nil or non-positive)
+	MaxPageSizeFunc func(c *gin.Context) int
+
+	// ComputedColumns are computed by the database and added to the SELECT list
+	ComputedColumns []ComputedColumn
+
+	// DateSearchColumns lists searchable date/time columns that are formatted
+	// as text (using DateSearchFormat) before matching the search term
+	DateSearchColumns []string
+
+	// DateSearchFormat is the dialect-specific format used for DateSearchColumns
+	// (empty means an ISO-like "YYYY-MM-DD HH:MM:SS" format)
+	DateSearchFormat string
+
+	// ArrayDataColumns, when set, outputs each row as an array of these
+	// columns' values (in order) instead of an object
+	ArrayDataColumns []string
+
+	// RetryAttempts is the total number of attempts for each database call
+	// (values below 2 disable retries)
+	RetryAttempts int
+
+	// RetryBackoff is the delay before the first retry, doubled after each attempt
+	RetryBackoff time.Duration
+
+	// RetryIf decides whether an error is transient and worth retrying
+	// (nil uses the default matcher for dropped connections and network errors)
+	RetryIf func(err error) bool
+
+	// SearchableHidden columns take part in global search but are removed from the output
+	SearchableHidden []string
+
+	// InitialSearch is the search value applied when the client sends an empty search
+	InitialSearch string
+
+	// GroupedCount counts through a subquery, for GROUP BY and DISTINCT base queries
+	GroupedCount bool
+
+	// ContextValues holds request-scoped values passed to AddWithValues and EditWithValues callbacks
+	ContextValues map[string]interface{}
+
+	// AddValueColumns contains custom columns computed from row data and ContextValues
+	AddValueColumns map[string]func(row, values map[string]interface{}) interface{}
+
+	// EditValueColumns contains column transformations that also receive ContextValues
+	EditValueColumns map[string]func(value interface{}, row, values map[string]interface{}) interface{}
+
+	// KeyCase converts all output row keys to snake_case or camelCase
+	KeyCase KeyCase
+
+	// SearchableMap maps frontend column names to the database columns searched for them
+	SearchableMap map[string]string
+
+	// BoolSearchColumns maps boolean columns to the search tokens matching true and false
+	BoolSearchColumns map[string]BoolTokens
+
+	// FetchHook is called with the populated dest pointer before rows are converted to maps
+	FetchHook func(dest interface{}) error
+
+	// MaxSearchLength caps the global search value, in characters
+	// (0 uses the default of 255, negative disables the cap)
+	MaxSearchLength int
+
+	// RejectLongSearch returns a ValidationError for search values over
+	// MaxSearchLength instead of truncating them
+	RejectLongSearch bool
+
+	// Dialect overrides the SQL dialect detected from the query's dialector
+	// ("postgres", "mysql", "sqlite", "sqlserver")
+	Dialect string
+
+	// PageParam and PerPageParam name the REST-style pagination query parameters
+	// used when start and length are absent (empty means "page" and "per_page")
+	PageParam    string
+	PerPageParam string
+
+	// TrimStrings trims leading and trailing whitespace from string cell values
+	TrimStrings bool
+
+	// Debug enables debugging helpers such as ExplainPlan
+	Debug bool
+
+	// SearchableValidator and OrderableValidator replace the default column name
+	// validation for searchable and orderable columns (nil uses isValidColumnName)
+	SearchableValidator func(name string) bool
+	OrderableValidator  func(name string) bool
+
+	// Logger receives warnings about suspicious configurations (nil disables them)
+	Logger Logger
+
+	// ColumnAliases renames output keys (from -> to) after Add and Edit, before Remove
+	ColumnAliases map[string]string
+
+	// StrictCounts fails requests whose filtered count exceeds the total count
+	// (by default the mismatch is only reported to the Logger)
+	StrictCounts bool
+
+	// ParamFilters apply conditions from the request parameters (see WithParamFilter)
+	ParamFilters []ParamFilter
+
+	// TransformOrder reorders the row transformation stages (empty keeps the default order)
+	TransformOrder []Stage
+
+	// FieldsParam names the query parameter listing the output fields to keep (empty disables it)
+	FieldsParam string
+
+	// ParamSource selects where the DataTables parameters are read from (default ParamSourceBoth)
+	ParamSource ParamSource
+
+	// OperatorColumns accept comparison operators (>, <, >=, <=, =, !=) in per-column search values
+	OperatorColumns []string
+
+	// GroupTotalColumn enables subtotal rows for each group of this output column (see WithGroupTotals)
+	GroupTotalColumn string
+
+	// GroupTotalSums lists the output columns summed in the group total rows
+	GroupTotalSums []string
+
+	// CountStrategy computes the filtered count (nil uses an exact count, or a subquery count with GroupedCount)
+	CountStrategy CountStrategy
+
+	// SearchPrefix namespaces the DataTables parameter names (e.g. "table1_" reads "table1_draw")
+	SearchPrefix string
+
+	// OrthogonalColumns output cells as {"display": ..., "sort": ...} objects (see WithOrthogonal)
+	OrthogonalColumns map[string]OrthogonalColumn
+
+	// PagePostProcess decorates the rows of the current page before the response
+	PagePostProcess func(rows []map[string]interface{}) ([]map[string]interface{}, error)
+
+	// ColumnACL reports whether the requesting user may see an output column (nil allows all)
+	ColumnACL func(c *gin.Context, column string) bool
+
+	// UnaccentSearch makes the search ignore diacritics (PostgreSQL and in-memory search)
+	UnaccentSearch bool
+
+	// IndexBase is the index of the first row (nil starts at 1, see WithIndexBase)
+	IndexBase *int
+
+	// SearchCallback replaces the built-in global search (nil uses the built-in search)
+	SearchCallback func(q *gorm.DB, searchable []string, value string) *gorm.DB
+
+	// TotalProvider supplies recordsTotal instead of the total count query (nil counts)
+	TotalProvider func() (int64, error)
+
+	// HTMLEscapeColumns lists columns whose string values are HTML-escaped
+	HTMLEscapeColumns []string
+
+	// HTMLEscapeAll HTML-escapes the string values of every column not in RawHTMLColumns
+	HTMLEscapeAll bool
+
+	// RawHTMLColumns lists columns containing trusted markup that are never escaped
+	RawHTMLColumns []string
+
+	// EmptyMessage is returned in the response's message field when no records match
+	EmptyMessage string
+
+	// MaxOffset caps the requested start offset (0 is unlimited)
+	MaxOffset int
+
+	// RejectDeepOffset returns a ValidationError for start offsets over
+	// MaxOffset instead of clamping them
+	RejectDeepOffset bool
+
+	// DeletedAtColumn is the soft-delete column read by WithDeletedFlag
+	DeletedAtColumn string
+
+	// DeletedFlagColumn receives whether DeletedAtColumn is set (empty disables the flag)
+	DeletedFlagColumn string
+
+	// ComputedOrder sorts by Add columns in Go when the client orders by one
+	ComputedOrder bool
+
+	// ComputedOrderFullSet fetches the whole filtered set for computed ordering
+	// instead of sorting only the current page
+	ComputedOrderFullSet bool
+
+	// ValueMapper transforms every cell value after Edit (nil leaves values unchanged)
+	ValueMapper func(column string, value interface{}) interface{}
+
+	// RowFilter drops fetched rows for which it returns false (nil keeps all rows)
+	RowFilter func(row map[string]interface{}) bool
+
+	// CursorColumn enables keyset pagination on a unique, sortable column (empty uses offsets)
+	CursorColumn string
+
+	// Timings attaches the durations of the database calls to the response
+	Timings bool
+
+	// NormalizeNumbers converts integer cell values to int64 and float32 values to float64
+	NormalizeNumbers bool
+
+	// SearchFallback searches the orderable columns when no searchable columns are given
+	SearchFallback bool
+
+	// ResultLimit hard-caps the fetched rows, even for length=-1 (0 is unlimited)
+	ResultLimit int
+
+	// Having lists HAVING conditions applied to the filtered query of grouped queries
+	Having []HavingClause
+
+	// TotalDB runs the unfiltered total count on a separate handle (nil uses the query's)
+	TotalDB *gorm.DB
+
+	// FetchDB runs the row fetch on a separate handle (nil uses the query's)
+	FetchDB *gorm.DB
+
+	// FooterAggregates lists aggregates over the filtered set returned in the response footer
+	FooterAggregates []FooterAggregate
+
+	// PureJSON makes the response helpers send HTML characters unescaped
+	PureJSON bool
+
+	// OmitEmpty drops zero-value cells (empty string, 0, nil, false) from the output rows
+	OmitEmpty bool
+}
+
+// BoolTokens lists the global search values that match a boolean column.
+// Tokens are matched case-insensitively against the whole search value.
+type BoolTokens struct {
+	True  []string
+	False []string
 }
 
 // NewOptions returns a new Options instance with sensible defaults.
@@ -109,3 +390,1430 @@ func (o Options) Remove(cols ...string) Options {
 	o.RemoveColumns = append(o.RemoveColumns, cols...)
 	return o
 }
+
+// WithMatchHighlight enables search match metadata on the returned rows.
+// When enabled and a global search is active, each row receives a "DT_Matches"
+// key mapping every matched searchable column to the positions of the term,
+// so the frontend can highlight matches without re-implementing the search.
+//
+// Matching is computed in Go over the returned page only.
+//
+// Example:
+//   opts.WithMatchHighlight(true)
+func (o Options) WithMatchHighlight(enabled bool) Options {
+	o.MatchHighlight = enabled
+	return o
+}
+
+// WithFlattenCollections converts slice and map fields into scalar strings,
+// since DataTables cells expect scalar values.
+//
+// Slices (e.g. []string tags) are joined using the given separator, while maps
+// are encoded as JSON strings. Byte slices are left untouched.
+//
+// Parameters:
+//   - sep: The separator used to join slice elements (e.g., ", ")
+//
+// Example:
+//   opts.WithFlattenCollections(", ")
+func (o Options) WithFlattenCollections(sep string) Options {
+	o.FlattenCollections = true
+	o.CollectionSeparator = sep
+	return o
+}
+
+// WithAutoSearchable enables automatic detection of searchable columns.
+// When enabled and the searchable slice passed to OfReturn is nil or empty,
+// all string-typed columns of the GORM model are searched.
+//
+// This is opt-in because passing an empty searchable slice is otherwise
+// a deliberate way to disable search.
+//
+// Example:
+//   opts.WithAutoSearchable(true)
+func (o Options) WithAutoSearchable(enabled bool) Options {
+	o.AutoSearchable = enabled
+	return o
+}
+
+// WithStableSort appends the given column (typically the primary key) as the
+// final ORDER BY term, after the client or default ordering.
+// This guarantees stable pagination when ordering by non-unique columns
+// such as "status". The column is skipped if it is already part of the ordering.
+//
+// Parameters:
+//   - pkColumn: The tiebreaker column (e.g., "id", "users.id")
+//
+// Example:
+//   opts.WithStableSort("id")
+func (o Options) WithStableSort(pkColumn string) Options {
+	o.StableSortColumn = pkColumn
+	return o
+}
+
+// WithPreserveNumeric protects the numeric type of the given columns.
+// Values converted from structs keep their Go numeric types, but an Edit
+// callback returning a string would turn the column into text and break
+// client-side numeric sorting.
+//
+// For preserved columns, a string returned by an Edit callback is coerced back
+// to a number (int64 or float64). If the string is not numeric, the original
+// value is kept and the Edit result is discarded.
+//
+// Parameters:
+//   - cols: One or more column names to preserve
+//
+// Example:
+//   opts.WithPreserveNumeric("price", "quantity")
+func (o Options) WithPreserveNumeric(cols ...string) Options {
+	o.PreserveNumericColumns = append(append([]string(nil), o.PreserveNumericColumns...), cols...)
+	return o
+}
+
+// WithQueryTimeout bounds all database calls made by OfReturn with a timeout
+// derived from the query's context, so a deadline or cancellation set with
+// query.WithContext still applies. Cancellation of the request (e.g. a client
+// disconnect) cancels the calls too.
+// When the timeout is exceeded, OfReturn returns a well-formed DataTables
+// response with an inline "query timed out" error (the "error" field DataTables
+// displays to the user) and a nil error, instead of failing the request.
+//
+// Parameters:
+//   - d: The maximum duration for the database calls (e.g., 5*time.Second)
+//
+// Example:
+//   opts.WithQueryTimeout(3 * time.Second)
+func (o Options) WithQueryTimeout(d time.Duration) Options {
+	o.QueryTimeout = d
+	return o
+}
+
+// WithSearchNormalizer registers string replacements applied to a searchable
+// column before matching, so formatted values can be found by their plain form.
+// The column is wrapped in nested REPLACE calls in SQL, and the same replacements
+// are applied to the search term.
+//
+// Replacement strings are passed as query parameters. The column name is
+// validated like other searchable columns.
+//
+// Parameters:
+//   - column: The searchable column to normalize (e.g., "phone")
+//   - replacements: Map of substring to replacement (e.g., {"-": "", " ": ""})
+//
+// Example:
+//   // "123" matches "+1-23" and "1 2 3"
+//   opts.WithSearchNormalizer("phone", map[string]string{"-": "", " ": ""})
+func (o Options) WithSearchNormalizer(column string, replacements map[string]string) Options {
+	normalizers := make(map[string]map[string]string, len(o.SearchNormalizers)+1)
+	for k, v := range o.SearchNormalizers {
+		normalizers[k] = v
+	}
+
+	copied := make(map[string]string, len(replacements))
+	for k, v := range replacements {
+		copied[k] = v
+	}
+	normalizers[column] = copied
+
+	o.SearchNormalizers = normalizers
+	return o
+}
+
+// WithOrderingDisabled ignores any ordering sent by the client, so only
+// DefaultOrder is applied (or no ORDER BY at all, leaving the database's
+// natural order). This prevents users from re-sorting by expensive columns.
+//
+// Example:
+//   opts.WithOrderingDisabled(true).WithDefaultOrder("published_at DESC")
+func (o Options) WithOrderingDisabled(disabled bool) Options {
+	o.OrderingDisabled = disabled
+	return o
+}
+
+// WithInMemorySearch performs the global search in Go instead of SQL.
+// This is a fallback for complex queries where a database-side search is hard
+// to express.
+//
+// When enabled and a search term is present, the whole (ordered) result set is
+// fetched, the searchable columns of every row are substring-matched against
+// the term (by their row keys, so removed and ACL-hidden columns are never
+// matched), recordsFiltered is recomputed from the matches, and the page is
+// sliced in memory. The searchable columns are not used for SQL conditions in this mode.
+//
+// Only use this for small datasets, since every matching request loads the full set.
+//
+// Example:
+//   opts.WithInMemorySearch(true)
+func (o Options) WithInMemorySearch(enabled bool) Options {
+	o.InMemorySearch = enabled
+	return o
+}
+
+// WithExactColumns makes the given searchable columns use equality (col = ?)
+// in the global search instead of a LIKE substring match.
+// This suits fixed-length identifier columns such as SKUs or ISO codes,
+// where "%x%" is both slower and too loose.
+//
+// Equality is case-sensitive unless the column's collation says otherwise.
+//
+// Parameters:
+//   - cols: One or more searchable columns to match exactly
+//
+// Example:
+//   opts.WithExactColumns("sku", "country_code")
+func (o Options) WithExactColumns(cols ...string) Options {
+	o.ExactColumns = append(append([]string(nil), o.ExactColumns...), cols...)
+	return o
+}
+
+// WithAbsoluteTotal makes recordsTotal count every row of the model's table,
+// ignoring the WHERE clauses of the base query passed to OfReturn.
+//
+// By default, recordsTotal counts the base query including its conditions
+// ("total in scope"), which is the standard DataTables semantic. With this
+// option, recordsTotal becomes "total in table" while recordsFiltered still
+// reflects the base query plus the search.
+//
+// Parameters:
+//   - model: The model whose table is counted (e.g., &User{})
+//
+// Example:
+//   // recordsTotal = all users, recordsFiltered = active users matching the search
+//   datatables.OfReturn(c, db.Model(&User{}).Where("active = ?", true), &users,
+//       searchable, orderable, datatables.NewOptions().WithAbsoluteTotal(&User{}))
+func (o Options) WithAbsoluteTotal(model interface{}) Options {
+	o.AbsoluteTotalModel = model
+	return o
+}
+
+// WithRowId sets the column used as the DOM row id (DT_RowId) of each row.
+// The value is converted to a string; rows with a nil value get no DT_RowId.
+//
+// Parameters:
+//   - column: The column holding the row identifier (e.g., "id")
+//
+// Example:
+//   opts.WithRowId("id")
+func (o Options) WithRowId(column string) Options {
+	o.RowIdColumn = column
+	return o
+}
+
+// WithRowIdFunc computes the DOM row id (DT_RowId) from the row data,
+// which is useful for tables with composite keys. It takes precedence over
+// WithRowId when both are set. An empty result omits DT_RowId for that row.
+//
+// Example:
+//   opts.WithRowIdFunc(func(row map[string]interface{}) string {
+//       return fmt.Sprintf("order-%v-%v", row["order_id"], row["line_id"])
+//   })
+func (o Options) WithRowIdFunc(fn func(row map[string]interface{}) string) Options {
+	o.RowIdFunc = fn
+	return o
+}
+
+// WithMaxPageSizeFunc resolves the maximum page size per request instead of
+// using the default limit of 500. This allows, for example, admins to export
+// more rows than regular users based on a role set by authorization middleware.
+// A non-positive result falls back to the default limit.
+//
+// Example:
+//   opts.WithMaxPageSizeFunc(func(c *gin.Context) int {
+//       if c.GetString("role") == "admin" {
+//           return 5000
+//       }
+//       return 100
+//   })
+func (o Options) WithMaxPageSizeFunc(fn func(c *gin.Context) int) Options {
+	o.MaxPageSizeFunc = fn
+	return o
+}
+
+// WithComputed adds a database-computed column to the fetched rows by selecting
+// rawSQLExpr AS alias. Unlike Add, which computes in Go, the value comes from
+// the database, so the alias can be used in the orderable map for ordering.
+//
+// The alias is validated like other column names. The expression is
+// developer-trusted and must never contain user input.
+// The destination struct needs a field mapped to the alias
+// (e.g. `gorm:"column:order_count;->"`) to receive the value.
+//
+// Parameters:
+//   - alias: The output column name (e.g., "order_count")
+//   - rawSQLExpr: The SQL expression (e.g., a correlated subquery)
+//
+// Example:
+//   opts.WithComputed("order_count",
+//       "SELECT COUNT(*) FROM orders WHERE orders.user_id = users.id")
+//   // orderable: map[string]string{"orders": "order_count"}
+func (o Options) WithComputed(alias, rawSQLExpr string) Options {
+	o.ComputedColumns = append(append([]ComputedColumn(nil), o.ComputedColumns...), ComputedColumn{Alias: alias, Expr: rawSQLExpr})
+	return o
+}
+
+// WithDateSearchColumns formats the given searchable date/time columns as text
+// before matching, so searching "2024-01" finds January 2024 rows regardless
+// of how the database stores timestamps.
+//
+// The format uses the syntax of the database's formatting function and is
+// passed as a query parameter. Leave it empty for an ISO-like
+// "YYYY-MM-DD HH:MM:SS" representation. The function depends on the dialect:
+//   - postgres:  TO_CHAR(col, format), e.g. "YYYY-MM-DD"
+//   - mysql:     DATE_FORMAT(col, format), e.g. "%Y-%m-%d"
+//   - sqlite:    strftime(format, col), e.g. "%Y-%m-%d"
+//   - sqlserver: FORMAT(col, format), e.g. "yyyy-MM-dd"
+//   - others:    CAST(col AS CHAR(19)), the format is ignored
+//
+// Parameters:
+//   - format: The dialect-specific date format (or "" for the default)
+//   - cols: One or more searchable date columns
+//
+// Example:
+//   // PostgreSQL
+//   opts.WithDateSearchColumns("DD/MM/YYYY", "created_at")
+func (o Options) WithDateSearchColumns(format string, cols ...string) Options {
+	o.DateSearchFormat = format
+	o.DateSearchColumns = append(append([]string(nil), o.DateSearchColumns...), cols...)
+	return o
+}
+
+// WithArrayData outputs the response data as an array of arrays
+// (DataTables' "array source") instead of an array of objects.
+// Each row is projected into the values of the given columns, in order,
+// matching integer columns[].data indexes on the client. Columns missing
+// from a row produce null values.
+//
+// When the request's columns[i][data] are all indexes into columns (as with
+// the ColReorder extension), each row follows the client's current column
+// order instead: position i holds the column at index columns[i][data].
+//
+// Parameters:
+//   - columns: The output columns in client column order
+//
+// Example:
+//   opts.WithArrayData([]string{"DT_RowIndex", "name", "email"})
+//   // data: [[1, "John", "john@example.com"], ...]
+func (o Options) WithArrayData(columns []string) Options {
+	o.ArrayDataColumns = append([]string(nil), columns...)
+	return o
+}
+
+// WithRetry retries the count and fetch queries on transient database errors,
+// which is useful behind connection poolers that occasionally drop connections.
+//
+// The backoff doubles after each failed attempt. Retries respect the request
+// context: they stop when its deadline passes, and errors caused by
+// cancellation or timeouts are never retried.
+//
+// Parameters:
+//   - attempts: Total number of attempts per query (including the first)
+//   - backoff: Delay before the first retry
+//
+// Example:
+//   opts.WithRetry(3, 50*time.Millisecond)
+func (o Options) WithRetry(attempts int, backoff time.Duration) Options {
+	o.RetryAttempts = attempts
+	o.RetryBackoff = backoff
+	return o
+}
+
+// WithRetryIf sets the matcher deciding which errors are retried by WithRetry.
+// By default, dropped connections (driver.ErrBadConn, io.ErrUnexpectedEOF)
+// and network errors are retried.
+//
+// Example:
+//   opts.WithRetry(3, 50*time.Millisecond).WithRetryIf(func(err error) bool {
+//       return strings.Contains(err.Error(), "connection reset")
+//   })
+func (o Options) WithRetryIf(fn func(err error) bool) Options {
+	o.RetryIf = fn
+	return o
+}
+
+// WithSearchableHidden makes columns searchable while removing them from the output,
+// e.g. searching by an internal code that should not be sent to the client.
+//
+// Search runs at the SQL level before rows are transformed, so a column removed
+// from display (here or with Remove) can still be searched. The columns are
+// appended to the searchable list and removed like Remove columns.
+//
+// Parameters:
+//   - cols: Column names to search on and hide
+//
+// Example:
+//   opts.WithSearchableHidden("internal_code")
+func (o Options) WithSearchableHidden(cols ...string) Options {
+	o.SearchableHidden = append(append([]string(nil), o.SearchableHidden...), cols...)
+	return o
+}
+
+// WithInitialSearch sets a default search value applied when the client sends
+// an empty search, so a link can open a pre-filtered view (e.g. "my open tickets")
+// without frontend code. A non-empty client search always takes precedence.
+//
+// Parameters:
+//   - value: The default global search value
+//
+// Example:
+//   opts.WithInitialSearch("open")
+func (o Options) WithInitialSearch(value string) Options {
+	o.InitialSearch = value
+	return o
+}
+
+// WithGroupedCount counts records by wrapping the query in a subquery
+// (SELECT COUNT(*) FROM (<query>)), so totals are accurate when the base query
+// uses Group or Distinct to return aggregated rows.
+//
+// Parameters:
+//   - grouped: Whether to count through a subquery
+//
+// Example:
+//   query := db.Model(&Product{}).Select("category, SUM(price) AS total").Group("category")
+//   opts.WithGroupedCount(true)
+func (o Options) WithGroupedCount(grouped bool) Options {
+	o.GroupedCount = grouped
+	return o
+}
+
+// WithContextValue stores a request-scoped value (feature flag, currency, locale)
+// that is passed to AddWithValues and EditWithValues callbacks, so they can stay
+// pure instead of capturing external state. Add and Edit callbacks are unaffected.
+//
+// Parameters:
+//   - key: The value name
+//   - value: The value
+//
+// Example:
+//   opts.WithContextValue("currency", "EUR")
+func (o Options) WithContextValue(key string, value interface{}) Options {
+	values := make(map[string]interface{}, len(o.ContextValues)+1)
+	for k, v := range o.ContextValues {
+		values[k] = v
+	}
+	values[key] = value
+	o.ContextValues = values
+	return o
+}
+
+// AddWithValues registers a new computed column like Add, whose callback also
+// receives the values set with WithContextValue. The callback must not modify values.
+//
+// Parameters:
+//   - col: The name of the new column
+//   - fn: A function that computes the column value from row data and context values
+//
+// Example:
+//   opts.WithContextValue("currency", "EUR").
+//       AddWithValues("price_label", func(row, values map[string]interface{}) interface{} {
+//           return fmt.Sprintf("%v %s", row["price"], values["currency"])
+//       })
+func (o Options) AddWithValues(col string, fn func(row, values map[string]interface{}) interface{}) Options {
+	columns := make(map[string]func(row, values map[string]interface{}) interface{}, len(o.AddValueColumns)+1)
+	for k, v := range o.AddValueColumns {
+		columns[k] = v
+	}
+	columns[col] = fn
+	o.AddValueColumns = columns
+	return o
+}
+
+// EditWithValues registers a column transformation like Edit, whose callback also
+// receives the values set with WithContextValue. The callback must not modify values.
+//
+// Parameters:
+//   - col: The name of the column to edit
+//   - fn: A function that transforms the column value
+//
+// Example:
+//   opts.WithContextValue("mask", true).
+//       EditWithValues("email", func(value interface{}, row, values map[string]interface{}) interface{} {
+//           if values["mask"] == true {
+//               return "***"
+//           }
+//           return value
+//       })
+func (o Options) EditWithValues(col string, fn func(value interface{}, row, values map[string]interface{}) interface{}) Options {
+	columns := make(map[string]func(value interface{}, row, values map[string]interface{}) interface{}, len(o.EditValueColumns)+1)
+	for k, v := range o.EditValueColumns {
+		columns[k] = v
+	}
+	columns[col] = fn
+	o.EditValueColumns = columns
+	return o
+}
+
+// WithKeyCase converts all output row keys, from struct fields and Add columns
+// alike, to a consistent case. This avoids mixed casing when some structs lack
+// JSON tags and fall back to PascalCase field names.
+//
+// Keys are converted after all column transformations, so Edit, Remove and
+// WithArrayData refer to the original keys. DataTables' reserved "DT_" keys
+// and the index column are not converted.
+//
+// Parameters:
+//   - keyCase: SnakeCase, CamelCase, or PreserveCase (the default)
+//
+// Example:
+//   opts.WithKeyCase(datatables.SnakeCase)
+//   // "ProductID" -> "product_id"
+func (o Options) WithKeyCase(keyCase KeyCase) Options {
+	o.KeyCase = keyCase
+	return o
+}
+
+// WithSearchableMap makes columns searchable through a frontend-name to
+// database-column mapping, like the orderable map, so frontend names resolve
+// to joined columns consistently for both search and ordering.
+// The mapped columns are searched in addition to the searchable slice,
+// and both keys and values are validated like orderable columns.
+//
+// Parameters:
+//   - columns: Map of frontend column names to database columns
+//
+// Example:
+//   query := db.Model(&User{}).
+//       Select("users.*, roles.name AS role").
+//       Joins("LEFT JOIN roles ON roles.id = users.role_id")
+//   opts.WithSearchableMap(map[string]string{"role": "roles.name"})
+func (o Options) WithSearchableMap(columns map[string]string) Options {
+	mapped := make(map[string]string, len(o.SearchableMap)+len(columns))
+	for k, v := range o.SearchableMap {
+		mapped[k] = v
+	}
+	for k, v := range columns {
+		mapped[k] = v
+	}
+	o.SearchableMap = mapped
+	return o
+}
+
+// WithBoolSearch makes a boolean column searchable through the global search box.
+// When the search value equals one of the tokens (case-insensitive), the
+// condition "column = true" or "column = false" joins the search OR group;
+// other search values do not match the column. The column is searched in
+// addition to the searchable slice.
+//
+// Parameters:
+//   - column: The boolean database column
+//   - trueTokens: Search values matching true (e.g., "yes", "active")
+//   - falseTokens: Search values matching false (e.g., "no", "inactive")
+//
+// Example:
+//   opts.WithBoolSearch("is_active", []string{"yes", "active"}, []string{"no", "inactive"})
+func (o Options) WithBoolSearch(column string, trueTokens, falseTokens []string) Options {
+	columns := make(map[string]BoolTokens, len(o.BoolSearchColumns)+1)
+	for k, v := range o.BoolSearchColumns {
+		columns[k] = v
+	}
+	columns[column] = BoolTokens{
+		True:  append([]string(nil), trueTokens...),
+		False: append([]string(nil), falseTokens...),
+	}
+	o.BoolSearchColumns = columns
+	return o
+}
+
+// WithFetchHook registers a hook called with the populated dest pointer
+// (e.g. *[]User) right after the records are fetched, before they are converted
+// to maps. The hook can enrich the typed slice in place, for instance by calling
+// an external service. An error returned by the hook aborts the request.
+//
+// Parameters:
+//   - fn: The hook receiving the dest pointer
+//
+// Example:
+//   opts.WithFetchHook(func(dest interface{}) error {
+//       users := dest.(*[]User)
+//       return avatars.Decorate(*users)
+//   })
+func (o Options) WithFetchHook(fn func(dest interface{}) error) Options {
+	o.FetchHook = fn
+	return o
+}
+
+// WithMaxSearchLength caps the length of the global search value, so very long
+// search strings are not matched against every searchable column.
+// By default, values are capped at 255 characters.
+//
+// Parameters:
+//   - n: Maximum search length in characters (negative disables the cap)
+//   - reject: true returns a ValidationError for longer values, false truncates them
+//
+// Example:
+//   opts.WithMaxSearchLength(100, true)
+func (o Options) WithMaxSearchLength(n int, reject bool) Options {
+	o.MaxSearchLength = n
+	o.RejectLongSearch = reject
+	return o
+}
+
+// WithDialect overrides the SQL dialect used for dialect-specific syntax
+// (such as date search formatting). By default, the dialect is detected from
+// the query's dialector name; set it explicitly for wrapped or unusual drivers.
+//
+// Parameters:
+//   - dialect: "postgres", "mysql", "sqlite", or "sqlserver"
+//
+// Example:
+//   opts.WithDialect("postgres")
+func (o Options) WithDialect(dialect string) Options {
+	o.Dialect = dialect
+	return o
+}
+
+// WithPageParams renames the REST-style pagination query parameters read when
+// a request has no start and length parameters, so the same endpoint can serve
+// both DataTables and page-based API clients.
+//
+// Parameters:
+//   - page: The 1-based page number parameter (default "page")
+//   - perPage: The page size parameter (default "per_page")
+//
+// Example:
+//   opts.WithPageParams("p", "limit")
+//   // GET /users?p=3&limit=20 -> start=40, length=20
+func (o Options) WithPageParams(page, perPage string) Options {
+	o.PageParam = page
+	o.PerPageParam = perPage
+	return o
+}
+
+// WithTrimStrings trims leading and trailing whitespace from all string cell
+// values, e.g. for data imported from spreadsheets with trailing spaces.
+// Trimming runs after Edit callbacks and before columns are removed;
+// nil and non-string values are left untouched.
+//
+// Parameters:
+//   - enabled: Whether to trim string values
+//
+// Example:
+//   opts.WithTrimStrings(true)
+func (o Options) WithTrimStrings(enabled bool) Options {
+	o.TrimStrings = enabled
+	return o
+}
+
+// WithDebug enables debugging helpers such as ExplainPlan, which are disabled by
+// default so they are never exposed unintentionally. Enable it only in
+// development, e.g. with gin.IsDebugging().
+//
+// Parameters:
+//   - enabled: Whether debugging helpers are available
+//
+// Example:
+//   opts.WithDebug(gin.IsDebugging())
+func (o Options) WithDebug(enabled bool) Options {
+	o.Debug = enabled
+	return o
+}
+
+// WithSearchableValidator replaces the column name validation applied to
+// searchable columns (including SearchableHidden and SearchableMap columns).
+// By default, names may contain letters, digits, underscores, and dots.
+//
+// The validator is the only protection against SQL injection through these
+// names: it must reject anything that is not a plain column identifier.
+//
+// Parameters:
+//   - fn: Reports whether a column name is allowed
+//
+// Example:
+//   // Single-table search only: forbid table.column notation
+//   opts.WithSearchableValidator(func(name string) bool {
+//       return datatables.IsValidColumnName(name) && !strings.Contains(name, ".")
+//   })
+func (o Options) WithSearchableValidator(fn func(name string) bool) Options {
+	o.SearchableValidator = fn
+	return o
+}
+
+// WithOrderableValidator replaces the column name validation applied to the
+// keys and values of the orderable map. By default, names may contain letters,
+// digits, underscores, and dots.
+//
+// The validator is the only protection against SQL injection through these
+// names: it must reject anything that is not a plain column identifier.
+//
+// Parameters:
+//   - fn: Reports whether a column name is allowed
+//
+// Example:
+//   opts.WithOrderableValidator(func(name string) bool {
+//       return strings.Count(name, ".") <= 1 && datatables.IsValidColumnName(name)
+//   })
+func (o Options) WithOrderableValidator(fn func(name string) bool) Options {
+	o.OrderableValidator = fn
+	return o
+}
+
+// WithLogger sets the logger receiving diagnostic warnings, such as ordering
+// by a column that is removed from the output, or a search value sent while
+// no searchable columns are configured. Warnings never fail a request.
+//
+// Parameters:
+//   - logger: Any Printf-style logger, e.g. *log.Logger
+//
+// Example:
+//   opts.WithLogger(log.Default())
+func (o Options) WithLogger(logger Logger) Options {
+	o.Logger = logger
+	return o
+}
+
+// WithColumnAlias renames an output key for this endpoint, independent of the
+// struct's JSON tags, keeping its value. Aliases are applied after Add and Edit
+// callbacks (which use the original key) and before Remove (which uses the alias).
+//
+// All aliases are applied at once, so chained aliases do not cascade
+// (a->b and b->c moves a to b and b to c). If the target key already exists,
+// it is overwritten by the aliased value.
+//
+// Parameters:
+//   - from: The original output key
+//   - to: The new output key
+//
+// Example:
+//   opts.WithColumnAlias("email_address", "email")
+func (o Options) WithColumnAlias(from, to string) Options {
+	aliases := make(map[string]string, len(o.ColumnAliases)+1)
+	for k, v := range o.ColumnAliases {
+		aliases[k] = v
+	}
+	aliases[from] = to
+	o.ColumnAliases = aliases
+	return o
+}
+
+// WithStrictCounts makes a filtered count greater than the total count fail the
+// request with a *CountMismatchError instead of only being reported to the Logger.
+// Such a mismatch is a sign of a join multiplying rows.
+//
+// Parameters:
+//   - strict: Whether count mismatches fail the request
+//
+// Example:
+//   opts.WithStrictCounts(true)
+func (o Options) WithStrictCounts(strict bool) Options {
+	o.StrictCounts = strict
+	return o
+}
+
+// WithParamFilter filters on a database column using a request parameter,
+// without writing a hook. The condition is applied only when the parameter is
+// present and non-empty, and its value is always passed as a bind parameter.
+// The parameter is read like the DataTables parameters, honoring
+// WithParamSource and WithSearchPrefix.
+// Filters can be chained and are combined with AND; they affect recordsFiltered
+// but not recordsTotal. They are ignored by OfParams, which has no request.
+//
+// Supported operators: "eq" (=), "like" (case-insensitive substring),
+// "gt" (>), "lt" (<), "gte" (>=) and "lte" (<=). An unknown operator or an
+// invalid column name fails the request with a ValidationError.
+//
+// Parameters:
+//   - queryParam: The request parameter name (without the search prefix)
+//   - dbColumn: The database column to compare
+//   - operator: The comparison operator
+//
+// Example:
+//   opts.WithParamFilter("status", "users.status", "eq").
+//       WithParamFilter("created_from", "users.created_at", "gte")
+func (o Options) WithParamFilter(queryParam, dbColumn, operator string) Options {
+	o.ParamFilters = append(append([]ParamFilter(nil), o.ParamFilters...), ParamFilter{
+		Param:    queryParam,
+		Column:   dbColumn,
+		Operator: operator,
+	})
+	return o
+}
+
+// WithTransformOrder reorders the row transformation pipeline. The default
+// order is StageIndex, StageAdd, StageEdit, StageTrim, StageAlias, StageRemove.
+//
+// Stages omitted from the order still run, after the listed ones and in default
+// order, so removing sensitive columns cannot be skipped by accident; unknown
+// and duplicate stages are ignored. With a custom order, Add and Edit callbacks
+// receive the row as transformed by the earlier stages, e.g. an Add placed
+// after Edit sees the edited values, and a column placed before Remove can be
+// used and then removed.
+//
+// Parameters:
+//   - stages: The stages in the order they should run
+//
+// Example:
+//   // Edit "price", derive "price_label" from the edited value, then drop "price"
+//   opts.WithTransformOrder([]datatables.Stage{
+//       datatables.StageIndex, datatables.StageEdit, datatables.StageAdd, datatables.StageRemove,
+//   })
+func (o Options) WithTransformOrder(stages []Stage) Options {
+	o.TransformOrder = append([]Stage(nil), stages...)
+	return o
+}
+
+// WithFieldSelection lets clients request a subset of the output fields through
+// a query parameter, e.g. "?fields=name,email", to reduce the payload. The SQL
+// SELECT is unchanged: rows are trimmed after all transformations, using the
+// final output keys (after aliases and key casing). DT_* columns and the index
+// column are always kept, unknown fields are ignored, and a parameter naming
+// no known field leaves the rows unchanged.
+//
+// Field selection does not apply to array data rows (WithArrayData) or to OfParams.
+//
+// Parameters:
+//   - param: The query parameter name (defaults to "fields" if empty)
+//
+// Example:
+//   opts.WithFieldSelection("fields")
+func (o Options) WithFieldSelection(param string) Options {
+	if param == "" {
+		param = defaultFieldsParam
+	}
+	o.FieldsParam = param
+	return o
+}
+
+// defaultFieldsParam is the query parameter used by WithFieldSelection("")
+const defaultFieldsParam = "fields"
+
+// WithParamSource controls where the DataTables parameters are read from.
+// ParamSourceBoth (the default) reads the query string and the request body
+// (JSON or form), query string values taking precedence over form values;
+// ParamSourceQuery reads the query string only; ParamSourceForm reads the body
+// only, so parameters cannot be smuggled through the URL of a POST.
+//
+// Parameters:
+//   - source: ParamSourceBoth, ParamSourceQuery or ParamSourceForm
+//
+// Example:
+//   opts.WithParamSource(datatables.ParamSourceForm)
+func (o Options) WithParamSource(source ParamSource) Options {
+	o.ParamSource = source
+	return o
+}
+
+// WithOperatorColumns lets per-column search values on numeric columns start
+// with a comparison operator: ">100", "<=50", "= 3" or "!=0" apply the
+// comparison to the number instead of a LIKE match. Values without an
+// operator, or with a non-numeric operand, are matched like any other column.
+//
+// Columns are listed by their searchable name or SearchableMap key, and must
+// be searchable to receive per-column searches.
+//
+// Parameters:
+//   - cols: Column names accepting operators
+//
+// Example:
+//   opts.WithOperatorColumns("price", "stock")
+func (o Options) WithOperatorColumns(cols ...string) Options {
+	o.OperatorColumns = append(append([]string(nil), o.OperatorColumns...), cols...)
+	return o
+}
+
+// WithGroupTotals appends aggregate rows for footer-group rendering: a subtotal
+// row after each group of consecutive rows sharing the same groupColumn value,
+// and a grand total row at the end of the page. Marker rows hold the sums of
+// sumColumns and DT_GroupTotal=true; the grand total row also holds
+// DT_GrandTotal=true. Their count is not included in recordsTotal or recordsFiltered.
+//
+// Totals are computed over the current page after all column transformations,
+// so the query must be ordered by the group column (e.g. with WithDefaultOrder)
+// for groups to be contiguous.
+//
+// Parameters:
+//   - groupColumn: The output column identifying a group
+//   - sumColumns: The output columns to sum
+//
+// Example:
+//   opts.WithDefaultOrder("account ASC").WithGroupTotals("account", "debit", "credit")
+func (o Options) WithGroupTotals(groupColumn string, sumColumns ...string) Options {
+	o.GroupTotalColumn = groupColumn
+	o.GroupTotalSums = append([]string(nil), sumColumns...)
+	return o
+}
+
+// WithCountStrategy plugs a custom strategy for the filtered count, replacing
+// GroupedCount for it. Built-in strategies are ExactCount, SubqueryCount and
+// ApproxCount; custom ones can, for example, read a maintained statistics table.
+// The total count is unaffected.
+//
+// Parameters:
+//   - strategy: The CountStrategy computing recordsFiltered
+//
+// Example:
+//   opts.WithCountStrategy(datatables.ApproxCount{})
+func (o Options) WithCountStrategy(strategy CountStrategy) Options {
+	o.CountStrategy = strategy
+	return o
+}
+
+// WithSearchPrefix namespaces the DataTables request parameters, so several
+// tables on one page can post to the same handler. With the prefix "table1_",
+// "table1_draw", "table1_start", "table1_search[value]", "table1_order[0][column]",
+// "table1_columns[0][data]" and the page parameters are read, and unprefixed
+// parameters are ignored. JSON bodies are not affected. The default empty
+// prefix reads the standard names.
+//
+// Parameters:
+//   - prefix: The parameter name prefix
+//
+// Example:
+//   opts := datatables.NewOptions().WithSearchPrefix(c.Query("table") + "_")
+func (o Options) WithSearchPrefix(prefix string) Options {
+	o.SearchPrefix = prefix
+	return o
+}
+
+// WithOrthogonal outputs a column as DataTables orthogonal data, so the client
+// shows formatted text while sorting by the raw value. The cell becomes
+// {"display": display(value, row), "sort": sort(value, row)}; a nil sort
+// function keeps the raw value. It runs after the Edit callbacks, receiving the
+// edited value, and the column is then referenced by its original key.
+//
+// On the client, point the column at the object with render: {_: "display", sort: "sort"}.
+//
+// Parameters:
+//   - column: The output column to split
+//   - display: Function producing the displayed value
+//   - sort: Function producing the sort value, or nil for the raw value
+//
+// Example:
+//   opts.WithOrthogonal("created_at",
+//       func(value interface{}, row map[string]interface{}) interface{} {
+//           return value.(time.Time).Format("02 Jan 2006")
+//       },
+//       func(value interface{}, row map[string]interface{}) interface{} {
+//           return value.(time.Time).Unix()
+//       })
+func (o Options) WithOrthogonal(
+	column string,
+	display func(value interface{}, row map[string]interface{}) interface{},
+	sort func(value interface{}, row map[string]interface{}) interface{},
+) Options {
+	columns := make(map[string]OrthogonalColumn, len(o.OrthogonalColumns)+1)
+	for k, v := range o.OrthogonalColumns {
+		columns[k] = v
+	}
+	columns[column] = OrthogonalColumn{Display: display, Sort: sort}
+	o.OrthogonalColumns = columns
+	return o
+}
+
+// WithPagePostProcess registers a hook for page-scoped enrichment, such as
+// expensive per-row API calls. It receives only the rows of the current page,
+// after pagination, conversion and all column transformations, and its returned
+// rows are used for the response. A returned error aborts the request.
+//
+// Parameters:
+//   - fn: Function receiving and returning the current page's rows
+//
+// Example:
+//   opts.WithPagePostProcess(func(rows []map[string]interface{}) ([]map[string]interface{}, error) {
+//       for _, row := range rows {
+//           row["avatar"] = avatars.URL(row["id"])
+//       }
+//       return rows, nil
+//   })
+func (o Options) WithPagePostProcess(fn func(rows []map[string]interface{}) ([]map[string]interface{}, error)) Options {
+	o.PagePostProcess = fn
+	return o
+}
+
+// WithColumnACL hides columns per request, e.g. based on the user's role,
+// which is more dynamic than a static Remove. The function is called once per
+// output column and request, after Add, Edit and aliases (so computed columns
+// are gated too and aliased columns use their alias), and columns it rejects are
+// dropped. DT_* columns and the index column are always kept.
+//
+// Hidden columns are also removed from the searchable and orderable columns
+// of the request, so their values cannot be probed through search[value],
+// per-column searches, or ordering. A searchable or orderable column is
+// hidden when the function rejects its frontend name, its database column
+// (with or without the table prefix), or an alias of either.
+//
+// Without a request (OfParams), the function receives an empty context: its
+// accessors such as c.GetString return zero values, but c.Request is nil.
+//
+// Parameters:
+//   - fn: Function reporting whether the column is visible for the request
+//
+// Example:
+//   opts.WithColumnACL(func(c *gin.Context, column string) bool {
+//       return column != "salary" || c.GetString("role") == "admin"
+//   })
+func (o Options) WithColumnACL(fn func(c *gin.Context, column string) bool) Options {
+	o.ColumnACL = fn
+	return o
+}
+
+// ClearColumns returns a copy of the options without any column
+// transformations: the Add, AddWithValues, Edit, EditWithValues and Remove
+// columns are replaced with new, empty collections, while scalar settings such
+// as IndexColumn and DefaultOrder are preserved. This is handy for deriving a
+// variant from a cached base: adding columns to the result never affects the base.
+//
+// Example:
+//   variant := base.ClearColumns().Add("action", actionColumn)
+func (o Options) ClearColumns() Options {
+	o.AddColumns = make(map[string]func(row map[string]interface{}) interface{})
+	o.EditColumns = make(map[string]func(value interface{}, row map[string]interface{}) interface{})
+	o.RemoveColumns = []string{}
+	o.AddValueColumns = nil
+	o.EditValueColumns = nil
+	return o
+}
+
+// WithUnaccentSearch makes the search diacritic-insensitive, so "jose" matches
+// "José". On PostgreSQL, searched columns and the term are wrapped in
+// unaccent(...), which requires the extension (CREATE EXTENSION unaccent).
+// In-memory search strips diacritics in Go. Other dialects are unchanged;
+// MySQL's default collations already ignore accents.
+//
+// Parameters:
+//   - enabled: Whether diacritics are ignored
+//
+// Example:
+//   opts.WithUnaccentSearch(true)
+func (o Options) WithUnaccentSearch(enabled bool) Options {
+	o.UnaccentSearch = enabled
+	return o
+}
+
+// WithIndexBase sets the index of the first row, generalizing the default
+// 1-based index: rows are numbered from n, plus the page offset unless
+// ResetIndex is set. n must be non-negative; a negative base fails the request
+// with a ValidationError (and is reported by Validate).
+//
+// Parameters:
+//   - n: The index of the first row (e.g. 0 for 0-based indexes)
+//
+// Example:
+//   opts.WithIndexBase(0)
+//   // start=20: DT_RowIndex 20, 21, ...
+func (o Options) WithIndexBase(n int) Options {
+	o.IndexBase = &n
+	return o
+}
+
+// indexBase returns the index of the first row, 1 unless set with WithIndexBase.
+func (o Options) indexBase() int {
+	if o.IndexBase == nil {
+		return 1
+	}
+	return *o.IndexBase
+}
+
+// WithSearchCallback replaces the built-in global search with custom logic,
+// such as full-text search (PostgreSQL tsvector, MySQL MATCH ... AGAINST).
+// The callback receives the query, the searchable columns, and the raw search
+// value, and returns the query with its conditions added. Pagination,
+// ordering, per-column searches, and transformations still apply.
+//
+// The callback bypasses the built-in escaping and column handling: the value
+// is passed exactly as the client sent it, so always bind it as a parameter
+// and never concatenate it into SQL. When the search is combined with other
+// filters, the callback's conditions are wrapped in parentheses, so it should
+// only add WHERE conditions.
+//
+// Parameters:
+//   - fn: Builds the search conditions for a non-empty search value
+//
+// Example:
+//   opts.WithSearchCallback(func(q *gorm.DB, searchable []string, value string) *gorm.DB {
+//       return q.Where("search_vector @@ plainto_tsquery('english', ?)", value)
+//   })
+func (o Options) WithSearchCallback(fn func(q *gorm.DB, searchable []string, value string) *gorm.DB) Options {
+	o.SearchCallback = fn
+	return o
+}
+
+// WithTotalProvider supplies recordsTotal from a cheaper source than the
+// unfiltered COUNT(*), such as a cached value, a statistics table, or
+// PostgreSQL's pg_class.reltuples. When set, the total count query is not
+// run; an error from the provider is returned by OfReturn as-is.
+//
+// recordsFiltered is always counted, even without filtering, so pagination
+// never depends on a stale or estimated total. For the same reason, a
+// filtered count above the provided total is not reported as a count mismatch.
+//
+// Parameters:
+//   - fn: Returns the unfiltered total
+//
+// Example:
+//   opts.WithTotalProvider(func() (int64, error) {
+//       return cache.UserCount(), nil
+//   })
+func (o Options) WithTotalProvider(fn func() (int64, error)) Options {
+	o.TotalProvider = fn
+	return o
+}
+
+// WithHTMLEscape HTML-escapes the string values of the given columns, for
+// clients that render cells as markup but expect plain text. Called without
+// columns, it escapes every string column except those marked with
+// WithRawHTML. Values of type template.HTML are trusted and never escaped.
+//
+// Escaping runs in StageTrim, after Add and Edit callbacks, so it also covers
+// values they return. Column names are those before WithColumnAlias renames.
+//
+// Parameters:
+//   - columns: Columns to escape (none escapes all string columns)
+//
+// Example:
+//   opts.WithHTMLEscape("name", "bio")
+//   // "<b>John</b>" → "&lt;b&gt;John&lt;/b&gt;"
+func (o Options) WithHTMLEscape(columns ...string) Options {
+	if len(columns) == 0 {
+		o.HTMLEscapeAll = true
+		return o
+	}
+	o.HTMLEscapeColumns = append(append([]string(nil), o.HTMLEscapeColumns...), columns...)
+	return o
+}
+
+// WithRawHTML marks columns as containing trusted markup, such as the
+// buttons of an "actions" column, so WithHTMLEscape leaves them raw.
+//
+// Parameters:
+//   - columns: Columns whose values are safe HTML
+//
+// Example:
+//   opts.WithHTMLEscape().WithRawHTML("actions")
+func (o Options) WithRawHTML(columns ...string) Options {
+	o.RawHTMLColumns = append(append([]string(nil), o.RawHTMLColumns...), columns...)
+	return o
+}
+
+// WithEmptyMessage sets a message returned in the response's "message" field
+// when no records match (recordsFiltered is 0), for server-driven empty table
+// messages. The field is omitted otherwise; it is independent of the "error"
+// field used for query timeouts.
+//
+// Parameters:
+//   - message: The message for empty results
+//
+// Example:
+//   opts.WithEmptyMessage("No matching records")
+//   // {"draw":1,"recordsTotal":50,"recordsFiltered":0,"data":[],"message":"No matching records"}
+func (o Options) WithEmptyMessage(message string) Options {
+	o.EmptyMessage = message
+	return o
+}
+
+// WithMaxOffset caps the start offset a client may request, guarding against
+// deep-pagination abuse: a request like start=10000000 makes the database
+// skip that many rows. By default, offsets are unlimited.
+//
+// This is only a guard. The real fix for browsing very large tables is
+// keyset (seek) pagination, which filters on the last seen key instead of
+// skipping rows.
+//
+// Parameters:
+//   - n: Maximum start offset (0 is unlimited)
+//   - reject: true returns a ValidationError for deeper offsets, false clamps them to n
+//
+// Example:
+//   opts.WithMaxOffset(10000, true)
+func (o Options) WithMaxOffset(n int, reject bool) Options {
+	o.MaxOffset = n
+	o.RejectDeepOffset = reject
+	return o
+}
+
+// WithDeletedFlag adds a boolean column reporting whether a row is soft-deleted,
+// derived from its deleted_at value being set. It pairs with queries using
+// GORM's Unscoped() for trash and restore views that list deleted rows.
+// Null values, nil pointers, zero times, and invalid gorm.DeletedAt values
+// count as not deleted. The flag is added in StageAdd.
+//
+// Parameters:
+//   - deletedAtColumn: The row's soft-delete column (e.g. "deleted_at")
+//   - outputColumn: The boolean output column (e.g. "is_deleted")
+//
+// Example:
+//   opts.WithDeletedFlag("deleted_at", "is_deleted").Remove("deleted_at")
+//   // {"id": 1, "is_deleted": true}
+func (o Options) WithDeletedFlag(deletedAtColumn, outputColumn string) Options {
+	o.DeletedAtColumn = deletedAtColumn
+	o.DeletedFlagColumn = outputColumn
+	return o
+}
+
+// WithComputedOrder lets clients order by Add columns, whose values are
+// computed in Go and cannot be ordered in SQL (such as a formatted full name).
+// When the requested order column is an Add column and not orderable, rows
+// are sorted in memory by the computed value; other columns are ordered in
+// SQL as usual.
+//
+// By default, only the fetched page is sorted: the page itself follows the
+// database order (DefaultOrder), so the computed order does not span pages.
+// With fullSet, the whole filtered set is fetched, sorted, and paginated in
+// Go, which is correct across pages but only suits small data sets.
+//
+// Parameters:
+//   - fullSet: true sorts the whole filtered set, false only the current page
+//
+// Example:
+//   opts.Add("full_name", fullName).WithComputedOrder(true)
+//   // ?order[0][column]=full_name&order[0][dir]=asc
+func (o Options) WithComputedOrder(fullSet bool) Options {
+	o.ComputedOrder = true
+	o.ComputedOrderFullSet = fullSet
+	return o
+}
+
+// WithValueMapper applies a transformation to every cell value, for blanket
+// rules such as masking PII columns by naming convention or rounding floats.
+// It runs in StageEdit after the Edit callbacks and before columns are
+// removed, so it sees added and edited values. Internal DT_* keys and the
+// index column are not mapped. Return the value unchanged to leave a cell as is.
+//
+// Parameters:
+//   - fn: Receives the column name and value, and returns the new value
+//
+// Example:
+//   opts.WithValueMapper(func(column string, value interface{}) interface{} {
+//       if strings.HasSuffix(column, "_ssn") {
+//           return "***"
+//       }
+//       return value
+//   })
+func (o Options) WithValueMapper(fn func(column string, value interface{}) interface{}) Options {
+	o.ValueMapper = fn
+	return o
+}
+
+// WithRowFilter drops rows from the current page for which the predicate
+// returns false, for checks that are hard to express in SQL, such as computed
+// permissions. The predicate sees each fetched row before any transformation,
+// including columns that are removed from the output.
+//
+// Filtering happens after pagination, so it skews the page: fewer rows than
+// requested are returned, and recordsTotal and recordsFiltered still count
+// the dropped rows. The response's recordsDropped field reports how many rows
+// were dropped from the page. Prefer SQL conditions where possible.
+//
+// Parameters:
+//   - fn: Returns true to keep the row
+//
+// Example:
+//   opts.WithRowFilter(func(row map[string]interface{}) bool {
+//       return acl.CanView(user, row["owner_id"])
+//   })
+func (o Options) WithRowFilter(fn func(row map[string]interface{}) bool) Options {
+	o.RowFilter = fn
+	return o
+}
+
+// WithCursor switches pagination to keyset (cursor) mode on a unique,
+// sortable column such as "id". Instead of skipping start rows with OFFSET,
+// each page fetches the next length rows after the last-seen value:
+//   WHERE column > ? ORDER BY column ASC LIMIT length
+// The client sends the last-seen value in the "cursor" request parameter
+// (empty for the first page) and the response's nextCursor field carries the
+// cursor of the next page, omitted on the last page.
+//
+// Keyset pages cost the same however deep they are, which suits infinite
+// scrolling and very large tables. The trade-off is DataTables' page-number
+// model: pages can only be walked forward from a cursor, start is ignored,
+// jumping to page N is not possible, and the client's ordering is replaced
+// by the cursor column. recordsTotal and recordsFiltered are still counted.
+//
+// The next cursor is read from the row field mapped to the column (whatever
+// its JSON key, including gorm.Model's ID); a full page whose rows do not
+// hold the column (e.g. json:"-") returns a ValidationError.
+//
+// Parameters:
+//   - column: The cursor column; it must be unique for stable pages
+//
+// Example:
+//   opts.WithCursor("id")
+//   // GET /api/users?length=50&cursor=1200
+//   // {"data": [...], "nextCursor": 1250}
+func (o Options) WithCursor(column string) Options {
+	o.CursorColumn = column
+	return o
+}
+
+// WithTimings attaches the durations of the database calls to the response
+// as a DT_Timings object, for performance dashboards and finding the slow
+// phase of a request in production:
+//   "DT_Timings": {"totalCount": 1.2, "filteredCount": 35.8, "fetch": 4.1}
+// Durations are in milliseconds and include retries.
+//
+// Parameters:
+//   - enabled: true attaches the timings
+//
+// Example:
+//   opts.WithTimings(true)
+func (o Options) WithTimings(enabled bool) Options {
+	o.Timings = enabled
+	return o
+}
+
+// WithNormalizeNumbers converts the numeric values of fetched rows to
+// consistent types before any callback runs: all integer kinds (int8 to
+// int64, uint8 to uint64) become int64 and float32 becomes float64, so
+// callbacks can use a single type assertion such as value.(int64).
+// uint64 values above the int64 range are kept, and named numeric types
+// (which may have their own JSON encoding) are unchanged. Rows scanned into
+// maps are always normalized this way. Disabled by default to keep existing
+// payloads unchanged.
+//
+// Parameters:
+//   - enabled: true normalizes the numbers of struct rows
+//
+// Example:
+//   opts.WithNormalizeNumbers(true).Edit("id", func(value interface{}, row map[string]interface{}) interface{} {
+//       return strconv.FormatInt(value.(int64), 10)
+//   })
+func (o Options) WithNormalizeNumbers(enabled bool) Options {
+	o.NormalizeNumbers = enabled
+	return o
+}
+
+// WithSearchFallback makes the global search fall back to the database
+// columns of the orderable map when no searchable columns are given, instead
+// of silently searching nothing. Disabled by default, so the searched columns
+// stay explicit. AutoSearchable takes precedence when both are set.
+//
+// Parameters:
+//   - enabled: true searches the orderable columns when searchable is empty
+//
+// Example:
+//   opts.WithSearchFallback(true)
+//   // OfReturn(c, query, &users, nil, map[string]string{"name": "name", "mail": "email"}, opts)
+//   // searches name and email
+func (o Options) WithSearchFallback(enabled bool) Options {
+	o.SearchFallback = enabled
+	return o
+}
+
+// WithResultLimit hard-caps the number of rows fetched per request, even when
+// the client requests all records with length=-1, so an export cannot dump a
+// whole table by accident. Unlike the maximum page size, it also applies to
+// length=-1. When the cap cuts the result short, the response's truncated
+// field is set so the client can tell the result is incomplete.
+//
+// Parameters:
+//   - n: Maximum number of rows per request (0 is unlimited)
+//
+// Example:
+//   opts.WithResultLimit(50000)
+//   // ?length=-1 on 1M rows: {"data": [... 50000 rows], "truncated": true}
+func (o Options) WithResultLimit(n int) Options {
+	o.ResultLimit = n
+	return o
+}
+
+// WithHaving adds a HAVING condition for queries with GROUP BY, filtering the
+// grouped results on their aggregates (e.g. customers with more than 5
+// orders). It applies to the filtered count and the fetch, not to
+// recordsTotal. Combine it with WithGroupedCount so the counts run through a
+// subquery. Multiple conditions are ANDed.
+//
+// The condition is developer-trusted SQL and is not validated; pass values
+// as parameterized args, never from the request by string concatenation.
+//
+// Parameters:
+//   - condition: SQL condition with ? placeholders (e.g. "COUNT(*) > ?")
+//   - args: Arguments for the placeholders
+//
+// Example:
+//   query := db.Table("orders").Select("customer_id, COUNT(*) AS orders").Group("customer_id")
+//   opts.WithGroupedCount(true).WithHaving("COUNT(*) > ?", 5)
+func (o Options) WithHaving(condition string, args ...interface{}) Options {
+	o.Having = append(append([]HavingClause(nil), o.Having...), HavingClause{Condition: condition, Args: args})
+	return o
+}
+
+// WithTotalDB runs the unfiltered total count (recordsTotal) on a separate
+// database handle, such as a read replica, for read/write splitting. The
+// count keeps the query's conditions, model, and context; only the
+// connection is taken from db.
+//
+// Parameters:
+//   - db: The handle counting the total (e.g. a replica opened with gorm.Open)
+//
+// Example:
+//   opts.WithTotalDB(replica).WithFetchDB(primary)
+func (o Options) WithTotalDB(db *gorm.DB) Options {
+	o.TotalDB = db
+	return o
+}
+
+// WithFetchDB runs the fetch of the page rows on a separate database handle,
+// keeping the query's conditions, ordering, pagination, and context; only
+// the connection is taken from db. The filtered count still runs on the
+// query passed to OfReturn, and the total count on WithTotalDB if set.
+//
+// Parameters:
+//   - db: The handle fetching the rows
+//
+// Example:
+//   opts.WithFetchDB(primary)
+func (o Options) WithFetchDB(db *gorm.DB) Options {
+	o.FetchDB = db
+	return o
+}
+
+// WithFooterAggregate adds an aggregate computed over the whole filtered set
+// (not just the current page), returned in the response's footer object for
+// DataTables' footerCallback. All aggregates run in a single query after the
+// filtered count; with WithGroupedCount, they aggregate the grouped rows
+// through a subquery, so expressions refer to the grouped query's columns.
+// In-memory search (WithInMemorySearch) is not reflected in the footer.
+//
+// The expression is developer-trusted SQL and is not validated; the output
+// key must be a valid column name.
+//
+// Parameters:
+//   - outputKey: The key in the footer object, also used as the SQL alias
+//   - expr: SQL aggregate expression (e.g. "SUM(amount)")
+//
+// Example:
+//   opts.WithFooterAggregate("total_amount", "SUM(amount)").WithFooterAggregate("rows", "COUNT(*)")
+//   // "footer": {"total_amount": 1520.5, "rows": 42}
+func (o Options) WithFooterAggregate(outputKey, expr string) Options {
+	o.FooterAggregates = append(append([]FooterAggregate(nil), o.FooterAggregates...), FooterAggregate{Key: outputKey, Expr: expr})
+	return o
+}
+
+// WithPureJSON makes the response helpers (JSON, JSONStatus, JSONRaw,
+// JSONAuto) and HTTPHandler send HTML characters as-is instead of escaping
+// them as \u003c, \u003e, and \u0026 the way Gin's c.JSON does. This keeps
+// HTML returned by Add and Edit callbacks (such as action buttons)
+// byte-for-byte intact in the response, as c.PureJSON does.
+//
+// OfReturn marks the Gin context, so the helpers must be called with the
+// same context. Escaping is a defence against the JSON being interpreted as
+// HTML; with it disabled, any user-controlled value in a cell rendered as
+// HTML is an XSS risk, so escape such values yourself (see WithHTMLEscape).
+//
+// Example:
+//   opts.WithPureJSON(true).Add("action", func(row map[string]interface{}) interface{} {
+//       return `<a href="/users/1">Edit</a>`
+//   })
+//   // "action": "<a href=\"/users/1\">Edit</a>" instead of "\u003ca href=..."
+func (o Options) WithPureJSON(enabled bool) Options {
+	o.PureJSON = enabled
+	return o
+}
+
+// WithOmitEmpty drops cells holding the zero value of their type (empty
+// string, 0, nil, false, zero time) from the output rows, like the
+// omitempty struct tag option that the converter otherwise ignores. This
+// shrinks payloads for sparse data, but clients must then handle missing
+// keys (for example with columns[].defaultContent), so it is opt-in.
+//
+// Zero values are dropped after all other transformations, so values
+// produced by Add and Edit callbacks are dropped too. DataTables' reserved
+// "DT_" keys and the index column are always kept.
+//
+// Example:
+//   opts.WithOmitEmpty(true)
+//   // {"id": 1, "name": "John", "email": "", "active": false} -> {"id": 1, "name": "John"}
+func (o Options) WithOmitEmpty(enabled bool) Options {
+	o.OmitEmpty = enabled
+	return o
+}
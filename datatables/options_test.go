@@ -135,3 +135,88 @@ func TestOptionsChaining(t *testing.T) {
 		t.Error("Chaining failed for Remove")
 	}
 }
+
+func TestOptionsWithFlattenCollections(t *testing.T) {
+	opts := NewOptions().WithFlattenCollections(", ")
+
+	if !opts.FlattenCollections {
+		t.Error("Expected FlattenCollections=true")
+	}
+	if opts.CollectionSeparator != ", " {
+		t.Errorf("Expected CollectionSeparator=', ', got %q", opts.CollectionSeparator)
+	}
+}
+
+func TestOptionsClearColumns(t *testing.T) {
+	base := NewOptions().
+		WithIndex("row_no", true).
+		WithDefaultOrder("created_at DESC").
+		Add("action", func(row map[string]interface{}) interface{} { return "edit" }).
+		Edit("name", func(value interface{}, row map[string]interface{}) interface{} { return value }).
+		AddWithValues("tenant", func(row, values map[string]interface{}) interface{} { return values["tenant"] }).
+		Remove("password")
+
+	variant := base.ClearColumns().
+		Add("badge", func(row map[string]interface{}) interface{} { return "new" }).
+		Edit("email", func(value interface{}, row map[string]interface{}) interface{} { return value }).
+		Remove("secret")
+
+	if variant.IndexColumn != "row_no" || !variant.ResetIndex || variant.DefaultOrder != "created_at DESC" {
+		t.Errorf("Expected scalar settings to be preserved, got %+v", variant)
+	}
+	if len(variant.AddColumns) != 1 || variant.AddColumns["badge"] == nil || len(variant.AddValueColumns) != 0 {
+		t.Errorf("Expected only the variant's Add column, got %v", variant.AddColumns)
+	}
+	if len(variant.EditColumns) != 1 || variant.EditColumns["email"] == nil {
+		t.Errorf("Expected only the variant's Edit column, got %v", variant.EditColumns)
+	}
+	if len(variant.RemoveColumns) != 1 || variant.RemoveColumns[0] != "secret" {
+		t.Errorf("Expected only the variant's Remove column, got %v", variant.RemoveColumns)
+	}
+
+	// The base is unaffected by the variant
+	if len(base.AddColumns) != 1 || base.AddColumns["action"] == nil || base.AddColumns["badge"] != nil {
+		t.Errorf("Expected base Add columns to be unchanged, got %v", base.AddColumns)
+	}
+	if len(base.EditColumns) != 1 || base.EditColumns["name"] == nil {
+		t.Errorf("Expected base Edit columns to be unchanged, got %v", base.EditColumns)
+	}
+	if len(base.AddValueColumns) != 1 {
+		t.Errorf("Expected base AddWithValues columns to be unchanged, got %v", base.AddValueColumns)
+	}
+	if len(base.RemoveColumns) != 1 || base.RemoveColumns[0] != "password" {
+		t.Errorf("Expected base Remove columns to be unchanged, got %v", base.RemoveColumns)
+	}
+}
+
+func TestOptionsBranchesDoNotShareSlices(t *testing.T) {
+	tests := []struct {
+		name   string
+		add    func(o Options, cols ...string) Options
+		column func(o Options) []string
+	}{
+		{"WithExactColumns", Options.WithExactColumns, func(o Options) []string { return o.ExactColumns }},
+		{"WithPreserveNumeric", Options.WithPreserveNumeric, func(o Options) []string { return o.PreserveNumericColumns }},
+		{"WithDateSearchColumns", func(o Options, cols ...string) Options { return o.WithDateSearchColumns("YYYY-MM-DD", cols...) }, func(o Options) []string { return o.DateSearchColumns }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Leave spare capacity in the base, so a shared append would be visible
+			base := tt.add(tt.add(NewOptions(), "a", "b", "c"), "d")
+
+			first := tt.add(base, "first")
+			second := tt.add(base, "second")
+
+			if got := strings.Join(tt.column(first), ","); got != "a,b,c,d,first" {
+				t.Errorf("Expected the first branch a,b,c,d,first, got %s", got)
+			}
+			if got := strings.Join(tt.column(second), ","); got != "a,b,c,d,second" {
+				t.Errorf("Expected the second branch a,b,c,d,second, got %s", got)
+			}
+			if got := strings.Join(tt.column(base), ","); got != "a,b,c,d" {
+				t.Errorf("Expected the base a,b,c,d, got %s", got)
+			}
+		})
+	}
+}
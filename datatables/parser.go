@@ -1,6 +1,14 @@
 package datatables
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -8,60 +16,539 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// defaultMaxPageSize is the maximum number of records per page unless
+// overridden with Options.WithMaxPageSizeFunc
+const defaultMaxPageSize = 500
+
 // ParseParams reads and normalizes query parameters used by the DataTables frontend.
 // It extracts pagination, sorting, and search information into a standardized dto.Params struct.
 //
 // Supported DataTables parameters:
 //   - draw: Draw counter for synchronization
 //   - start: Record offset for pagination
-//   - length: Number of records per page (max 500)
+//   - length: Number of records per page (max 500); 0 returns no rows (counts only), -1 all rows
 //   - search[value]: Global search value
-//   - order[0][column]: Column to order by
+//   - order[0][column]: Column to order by, as a name or as an index into
+//     columns[i] (resolved to columns[i][data], or columns[i][name] when data is empty)
 //   - order[0][dir]: Order direction (asc/desc)
+//   - order[i][column], order[i][dir]: All ordering entries, collected into Params.Orders
+//   - columns[i][search][value]: Per-column search values, collected into Params.ColumnSearches
+//   - columns[i][data], columns[i][name]: Client column definitions, collected into Params.Columns
+//
+// When start and length are both absent, REST-style page and per_page
+// parameters are used instead (start = (page-1)*per_page). Their names can be
+// changed with Options.WithPageParams.
+//
+// With Options.WithSearchPrefix, every parameter name above is read with the
+// prefix (e.g. "table1_draw", "table1_search[value]").
+//
+// Requests sent with "Content-Type: application/json" are decoded from the
+// body instead (see jsonRequest). If the body cannot be decoded, the query
+// parameters are used as a fallback. Form bodies (e.g. DataTables' POST type)
+// are read too, filling in parameters missing from the query string (which
+// takes precedence, as for GET requests); see Options.WithParamSource to
+// restrict the source.
 //
 // Returns a dto.Params struct with parsed values and sensible defaults.
 func ParseParams(c *gin.Context) dto.Params {
+	return parseParams(c, Options{})
+}
+
+// parseParams implements ParseParams with the page size limit, pagination
+// parameter names and parameter source configured in opts.
+func parseParams(c *gin.Context, opts Options) dto.Params {
+	maxLength := maxPageSize(c, opts)
+
+	if opts.ParamSource != ParamSourceQuery && isJSONRequest(c) {
+		if params, ok := parseJSONParams(c); ok {
+			return normalizeParams(params, maxLength)
+		}
+	}
+
+	values := paramValues(c, opts)
+
 	// Parse draw counter (used by DataTables for synchronization)
-	draw, _ := strconv.ParseInt(c.DefaultQuery("draw", "1"), 10, 64)
+	draw, _ := strconv.ParseInt(valueOrDefault(values, "draw", "1"), 10, 64)
 
 	// Parse pagination parameters
-	start, _ := strconv.Atoi(c.DefaultQuery("start", "0"))
-	length, _ := strconv.Atoi(c.DefaultQuery("length", "10"))
+	start, _ := strconv.Atoi(valueOrDefault(values, "start", "0"))
+	length, err := strconv.Atoi(valueOrDefault(values, "length", "10"))
+	if err != nil {
+		// An invalid length must not read as 0 (counts only)
+		length = 10
+	}
+	if !values.Has("start") && !values.Has("length") {
+		start, length = parsePage(values, opts, start, length, maxLength)
+	}
 
 	// Parse search value
-	search := c.DefaultQuery("search[value]", "")
+	search := valueOrDefault(values, "search[value]", "")
 
-	// Try to get order column from different possible sources
-	orderColumn := c.DefaultQuery("order[0][column]", "")
-	order := ""
+	// Resolve the order column: a column index refers to columns[i]
+	// (data, then name), a column name is used directly
+	orderColumn := valueOrDefault(values, "order[0][column]", "")
+	order := resolveOrderColumn(values, orderColumn)
+	if orderColumn == "" {
+		// Fallback: the first column, for clients sending no order
+		order = resolveColumnIndex(values, "0")
+	}
 
-	// First try: direct column name from order[0][column]
-	if orderColumn != "" {
-		order = orderColumn
-	} else {
-		// Fallback: try the old DataTables format (column index)
-		columnIndex := c.DefaultQuery("order[0][column]", "0")
-		order = c.DefaultQuery("columns["+columnIndex+"][data]", "")
+	return normalizeParams(dto.Params{
+		Draw:   draw,
+		Start:  start,
+		Length: length,
+		Search: search,
+		Order:  order,
+		Dir:    valueOrDefault(values, "order[0][dir]", "asc"),
+		Orders: parseOrderValues(values),
+
+		ColumnSearches: parseColumnSearches(values),
+		Cursor:         valueOrDefault(values, cursorParam, ""),
+		Columns:        parseColumns(values),
+	}, maxLength)
+}
+
+// cursorParam is the request parameter carrying the last-seen cursor value
+// in keyset mode (see Options.WithCursor)
+const cursorParam = "cursor"
+
+// ParamSource selects where ParseParams reads the DataTables parameters from.
+type ParamSource int
+
+const (
+	// ParamSourceBoth reads the query string and the request body (JSON or form),
+	// the query string taking precedence over form values present in both (the default)
+	ParamSourceBoth ParamSource = iota
+	// ParamSourceQuery reads the query string only, ignoring any body
+	ParamSourceQuery
+	// ParamSourceForm reads the request body only (JSON or form), ignoring the query string
+	ParamSourceForm
+)
+
+// defaultMultipartMemory matches Gin's default limit for parsing multipart forms
+const defaultMultipartMemory = 32 << 20
+
+// requestValues collects the request parameters from the given source.
+// Form bodies are parsed like Gin's PostForm, so the handler can still read
+// them with c.PostForm afterwards.
+func requestValues(c *gin.Context, source ParamSource) url.Values {
+	values := url.Values{}
+	if c.Request == nil {
+		return values
 	}
 
+	if source != ParamSourceForm && c.Request.URL != nil {
+		for key, vs := range c.Request.URL.Query() {
+			values[key] = vs
+		}
+	}
+
+	if source != ParamSourceQuery {
+		if err := c.Request.ParseMultipartForm(defaultMultipartMemory); err == nil || errors.Is(err, http.ErrNotMultipart) {
+			for key, vs := range c.Request.PostForm {
+				if !values.Has(key) {
+					values[key] = vs
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// paramValues returns the request parameters read from opts.ParamSource, with
+// opts.SearchPrefix removed when set. Returns no values for a nil context.
+func paramValues(c *gin.Context, opts Options) url.Values {
+	if c == nil {
+		return url.Values{}
+	}
+
+	values := requestValues(c, opts.ParamSource)
+	if opts.SearchPrefix != "" {
+		values = unprefixValues(values, opts.SearchPrefix)
+	}
+	return values
+}
+
+// unprefixValues returns the parameters starting with prefix, with the prefix
+// removed (e.g. "table1_search[value]" becomes "search[value]"). Parameters
+// without the prefix are dropped.
+func unprefixValues(values url.Values, prefix string) url.Values {
+	out := url.Values{}
+	for key, vs := range values {
+		if strings.HasPrefix(key, prefix) {
+			out[strings.TrimPrefix(key, prefix)] = vs
+		}
+	}
+	return out
+}
+
+// valueOrDefault returns the first value of key, or def if the key is absent
+// (like gin's DefaultQuery, a present but empty value is returned as is).
+func valueOrDefault(values url.Values, key, def string) string {
+	if vs, ok := values[key]; ok && len(vs) > 0 {
+		return vs[0]
+	}
+	return def
+}
+
+// Default REST-style pagination parameter names
+const (
+	defaultPageParam    = "page"
+	defaultPerPageParam = "per_page"
+)
+
+// parsePage computes start and length from the page and per_page parameters.
+// Pages are 1-based; a missing or invalid page is the first page, and a missing
+// per_page keeps the given length. The page size is capped at maxLength before
+// the offset is computed. Returns start and length unchanged when neither
+// parameter is present.
+func parsePage(values url.Values, opts Options, start, length, maxLength int) (int, int) {
+	pageParam := defaultString(opts.PageParam, defaultPageParam)
+	perPageParam := defaultString(opts.PerPageParam, defaultPerPageParam)
+
+	pageValue, hasPage := valueOrDefault(values, pageParam, ""), values.Has(pageParam)
+	perPageValue, hasPerPage := valueOrDefault(values, perPageParam, ""), values.Has(perPageParam)
+	if !hasPage && !hasPerPage {
+		return start, length
+	}
+
+	if n, err := strconv.Atoi(perPageValue); err == nil && hasPerPage {
+		length = n
+	}
+	if length > maxLength {
+		length = maxLength
+	}
+
+	page, err := strconv.Atoi(pageValue)
+	if err != nil || page < 1 {
+		page = 1
+	}
+	if length > 0 {
+		start = (page - 1) * length
+	}
+
+	return start, length
+}
+
+// normalizeParams validates the order direction, clamps negative offsets,
+// and enforces the page size limit.
+func normalizeParams(params dto.Params, maxLength int) dto.Params {
 	// Parse and validate order direction
-	dir := strings.ToLower(c.DefaultQuery("order[0][dir]", "asc"))
-	if dir != "asc" && dir != "desc" {
-		dir = "asc" // Default to ascending if invalid
+	params.Dir = normalizeDir(params.Dir)
+
+	// Negative offsets are meaningless; clamp to the first record
+	if params.Start < 0 {
+		params.Start = 0
 	}
 
 	// Enforce maximum page size to prevent abuse
 	// -1 means "all records" and is allowed
-	if length > 500 && length != -1 {
-		length = 500
+	if params.Length > maxLength && params.Length != -1 {
+		params.Length = maxLength
 	}
 
-	return dto.Params{
-		Draw:   draw,
-		Start:  start,
-		Length: length,
-		Search: search,
-		Order:  order,
-		Dir:    dir,
+	return params
+}
+
+// orderKeyPattern matches DataTables order keys such as "order[2][column]"
+var orderKeyPattern = regexp.MustCompile(`^order\[(\d+)\]\[(column|dir)\]$`)
+
+// parseOrderValues reads every order[i] entry, in index order.
+// Indices do not need to be contiguous (e.g. order[0] and order[2] are both read).
+//
+// Each column value is resolved like DataTables sends it: a numeric value
+// refers to columns[i][data] when that key exists (or columns[i][name] when
+// data is empty), otherwise the value is used directly as the column name.
+// Entries without a column are skipped.
+func parseOrderValues(values url.Values) []dto.Order {
+	indexSet := make(map[int]bool)
+	for key := range values {
+		if m := orderKeyPattern.FindStringSubmatch(key); m != nil {
+			idx, _ := strconv.Atoi(m[1])
+			indexSet[idx] = true
+		}
+	}
+
+	indices := make([]int, 0, len(indexSet))
+	for idx := range indexSet {
+		indices = append(indices, idx)
 	}
+	sort.Ints(indices)
+
+	orders := make([]dto.Order, 0, len(indices))
+	for _, idx := range indices {
+		prefix := "order[" + strconv.Itoa(idx) + "]"
+
+		column := resolveOrderColumn(values, values.Get(prefix+"[column]"))
+		if column == "" {
+			continue
+		}
+
+		orders = append(orders, dto.Order{
+			Column: column,
+			Dir:    normalizeDir(values.Get(prefix + "[dir]")),
+		})
+	}
+
+	return orders
+}
+
+// columnSearchPattern matches DataTables per-column search keys such as "columns[2][search][value]"
+var columnSearchPattern = regexp.MustCompile(`^columns\[(\d+)\]\[search\]\[value\]$`)
+
+// parseColumnSearches reads every non-empty columns[i][search][value], in
+// index order, keyed by columns[i][data] (or columns[i][name] when data is empty).
+func parseColumnSearches(values url.Values) []dto.ColumnSearch {
+	var indices []int
+	for key := range values {
+		if m := columnSearchPattern.FindStringSubmatch(key); m != nil {
+			idx, _ := strconv.Atoi(m[1])
+			indices = append(indices, idx)
+		}
+	}
+	sort.Ints(indices)
+
+	var searches []dto.ColumnSearch
+	for _, idx := range indices {
+		prefix := "columns[" + strconv.Itoa(idx) + "]"
+
+		value := values.Get(prefix + "[search][value]")
+		column := resolveColumnIndex(values, strconv.Itoa(idx))
+		if value == "" || column == "" {
+			continue
+		}
+
+		searches = append(searches, dto.ColumnSearch{Column: column, Value: value})
+	}
+
+	return searches
+}
+
+// columnDefinitionPattern matches DataTables column definition keys such as "columns[2][data]"
+var columnDefinitionPattern = regexp.MustCompile(`^columns\[(\d+)\]\[(data|name)\]$`)
+
+// parseColumns reads every columns[i] definition, in index order, as
+// columns[i][data] (or columns[i][name] when data is empty).
+func parseColumns(values url.Values) []string {
+	indexSet := make(map[int]bool)
+	for key := range values {
+		if m := columnDefinitionPattern.FindStringSubmatch(key); m != nil {
+			idx, _ := strconv.Atoi(m[1])
+			indexSet[idx] = true
+		}
+	}
+
+	indices := make([]int, 0, len(indexSet))
+	for idx := range indexSet {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+
+	var columns []string
+	for _, idx := range indices {
+		columns = append(columns, resolveColumnIndex(values, strconv.Itoa(idx)))
+	}
+
+	return columns
+}
+
+// resolveOrderColumn resolves an order[i][column] value like DataTables
+// sends it: a numeric value refers to columns[i] when it is defined there
+// (see resolveColumnIndex), otherwise the value is used directly as the
+// column name.
+func resolveOrderColumn(values url.Values, column string) string {
+	if _, err := strconv.Atoi(column); err == nil {
+		if resolved := resolveColumnIndex(values, column); resolved != "" {
+			return resolved
+		}
+	}
+	return column
+}
+
+// resolveColumnIndex returns columns[index][data], or columns[index][name]
+// when data is empty, as setups naming their columns send them.
+// Returns an empty string if neither is set.
+func resolveColumnIndex(values url.Values, index string) string {
+	prefix := "columns[" + index + "]"
+	if data := values.Get(prefix + "[data]"); data != "" {
+		return data
+	}
+	return values.Get(prefix + "[name]")
+}
+
+// normalizeDir trims, lowercases and validates an order direction, so "DESC ",
+// " desc" and "Desc" are all "desc".
+// Returns "asc" for anything other than "asc" or "desc".
+func normalizeDir(dir string) string {
+	dir = strings.ToLower(strings.TrimSpace(dir))
+	if dir != "asc" && dir != "desc" {
+		return "asc" // Default to ascending if invalid
+	}
+	return dir
+}
+
+// jsonRequest mirrors the payload DataTables sends when configured to
+// post its parameters as a JSON body.
+//
+// Example:
+//   {
+//     "draw": 1, "start": 0, "length": 10,
+//     "search": {"value": "john"},
+//     "order": [{"column": 0, "dir": "asc"}],
+//     "columns": [{"data": "name", "name": ""}]
+//   }
+//
+// Draw, start, and length may be sent as numbers or as numeric strings
+// (see jsonInt), since clients differ in how they encode them.
+type jsonRequest struct {
+	Draw   jsonInt         `json:"draw"`
+	Start  jsonInt         `json:"start"`
+	Length jsonInt         `json:"length"`
+	Cursor json.RawMessage `json:"cursor"`
+	Search struct {
+		Value string `json:"value"`
+	} `json:"search"`
+	Order []struct {
+		// Column is either a column index (number) or a column name (string)
+		Column json.RawMessage `json:"column"`
+		Dir    string          `json:"dir"`
+	} `json:"order"`
+	Columns []struct {
+		// Data is a property name, or an index with array data (see jsonString)
+		Data   jsonString `json:"data"`
+		Name   string     `json:"name"`
+		Search struct {
+			Value string `json:"value"`
+		} `json:"search"`
+	} `json:"columns"`
+}
+
+// jsonInt is an integer decoded from a JSON number or a numeric string,
+// e.g. both 10 and "10". An empty string or null leaves it unset, so the
+// default applies as it does for an empty query parameter.
+type jsonInt struct {
+	value int64
+	set   bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *jsonInt) UnmarshalJSON(data []byte) error {
+	value := strings.TrimSpace(strings.Trim(string(data), `"`))
+	if value == "" || value == "null" {
+		*n = jsonInt{}
+		return nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	*n = jsonInt{value: parsed, set: true}
+	return nil
+}
+
+// jsonString is a string decoded from a JSON string or a number, e.g. both
+// "0" and 0, since DataTables sends columns[].data as an integer index with
+// array data. null and other types (such as the object form of data) decode
+// as an empty string.
+type jsonString string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *jsonString) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = jsonString(str)
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err == nil {
+		*s = jsonString(num)
+		return nil
+	}
+
+	*s = ""
+	return nil
+}
+
+// isJSONRequest reports whether the request body is JSON.
+func isJSONRequest(c *gin.Context) bool {
+	return c.Request != nil && c.Request.Body != nil && c.ContentType() == gin.MIMEJSON
+}
+
+// parseJSONParams decodes DataTables parameters from a JSON request body.
+// The body is restored afterwards so it can be read again by the handler.
+// Returns false if the body is empty or not valid JSON.
+func parseJSONParams(c *gin.Context) (dto.Params, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		return dto.Params{}, false
+	}
+
+	var req jsonRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return dto.Params{}, false
+	}
+
+	params := dto.Params{
+		Draw:   req.Draw.value,
+		Start:  int(req.Start.value),
+		Length: 10,
+		Search: req.Search.Value,
+		Dir:    "asc",
+	}
+	if params.Draw == 0 {
+		params.Draw = 1
+	}
+	if req.Length.set {
+		params.Length = int(req.Length.value)
+	}
+	if len(req.Cursor) > 0 && string(req.Cursor) != "null" {
+		// The cursor may be a number or a string
+		var cursor string
+		if err := json.Unmarshal(req.Cursor, &cursor); err != nil {
+			cursor = string(req.Cursor)
+		}
+		params.Cursor = cursor
+	}
+
+	for i, o := range req.Order {
+		// A numeric column refers to the columns array; a string is a direct column name
+		column := ""
+		var index int
+		var name string
+		if err := json.Unmarshal(o.Column, &index); err == nil {
+			if index >= 0 && index < len(req.Columns) {
+				column = string(req.Columns[index].Data)
+				if column == "" {
+					column = req.Columns[index].Name
+				}
+			}
+		} else if err := json.Unmarshal(o.Column, &name); err == nil {
+			column = name
+		}
+
+		if i == 0 {
+			params.Order = column
+			params.Dir = o.Dir
+		}
+		if column != "" {
+			params.Orders = append(params.Orders, dto.Order{Column: column, Dir: normalizeDir(o.Dir)})
+		}
+	}
+
+	for _, col := range req.Columns {
+		column := string(col.Data)
+		if column == "" {
+			column = col.Name
+		}
+		params.Columns = append(params.Columns, column)
+		if col.Search.Value != "" && column != "" {
+			params.ColumnSearches = append(params.ColumnSearches, dto.ColumnSearch{Column: column, Value: col.Search.Value})
+		}
+	}
+
+	return params, true
 }
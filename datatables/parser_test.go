@@ -0,0 +1,518 @@
+package datatables
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// newJSONContext builds a Gin context for a POST request with a JSON body.
+func newJSONContext(body string) *gin.Context {
+	c, _ := newTestContext("")
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c
+}
+
+func TestParseParamsQuery(t *testing.T) {
+	c, _ := newTestContext("draw=2&start=20&length=10&search[value]=john&order[0][column]=name&order[0][dir]=DESC")
+
+	params := ParseParams(c)
+
+	if params.Draw != 2 || params.Start != 20 || params.Length != 10 {
+		t.Errorf("Unexpected pagination: %+v", params)
+	}
+	if params.Search != "john" {
+		t.Errorf("Expected search='john', got %q", params.Search)
+	}
+	if params.Order != "name" || params.Dir != "desc" {
+		t.Errorf("Expected order name desc, got %q %q", params.Order, params.Dir)
+	}
+}
+
+func TestParseParamsJSONBody(t *testing.T) {
+	t.Run("Column index resolves through columns array", func(t *testing.T) {
+		c := newJSONContext(`{
+			"draw": 4, "start": 30, "length": 15,
+			"search": {"value": "jane"},
+			"order": [{"column": 1, "dir": "desc"}],
+			"columns": [{"data": "id"}, {"data": "email"}]
+		}`)
+
+		params := ParseParams(c)
+
+		if params.Draw != 4 || params.Start != 30 || params.Length != 15 {
+			t.Errorf("Unexpected pagination: %+v", params)
+		}
+		if params.Search != "jane" {
+			t.Errorf("Expected search='jane', got %q", params.Search)
+		}
+		if params.Order != "email" || params.Dir != "desc" {
+			t.Errorf("Expected order email desc, got %q %q", params.Order, params.Dir)
+		}
+	})
+
+	t.Run("Column name is used directly", func(t *testing.T) {
+		c := newJSONContext(`{"order": [{"column": "name", "dir": "asc"}]}`)
+
+		params := ParseParams(c)
+
+		if params.Order != "name" {
+			t.Errorf("Expected order='name', got %q", params.Order)
+		}
+		if params.Draw != 1 || params.Length != 10 {
+			t.Errorf("Expected defaults draw=1 length=10, got %+v", params)
+		}
+	})
+
+	t.Run("Length is capped and direction validated", func(t *testing.T) {
+		c := newJSONContext(`{"length": 10000, "order": [{"column": "name", "dir": "sideways"}]}`)
+
+		params := ParseParams(c)
+
+		if params.Length != 500 {
+			t.Errorf("Expected length=500, got %d", params.Length)
+		}
+		if params.Dir != "asc" {
+			t.Errorf("Expected dir='asc', got %q", params.Dir)
+		}
+	})
+
+	t.Run("Body remains readable", func(t *testing.T) {
+		c := newJSONContext(`{"draw": 1}`)
+
+		ParseParams(c)
+
+		body, _ := io.ReadAll(c.Request.Body)
+		if string(body) != `{"draw": 1}` {
+			t.Errorf("Expected body to be restored, got %q", body)
+		}
+	})
+
+	t.Run("Invalid JSON falls back to query", func(t *testing.T) {
+		c := newJSONContext(`not json`)
+		c.Request.URL.RawQuery = "draw=9"
+
+		params := ParseParams(c)
+
+		if params.Draw != 9 {
+			t.Errorf("Expected fallback draw=9, got %d", params.Draw)
+		}
+	})
+}
+
+func TestParseParamsJSONNumbers(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		draw          int64
+		start, length int
+	}{
+		{"Numbers", `{"draw": 2, "start": 20, "length": 5}`, 2, 20, 5},
+		{"Numeric strings", `{"draw": "2", "start": "20", "length": "5"}`, 2, 20, 5},
+		{"Mixed encodings", `{"draw": "3", "start": 40, "length": "-1"}`, 3, 40, -1},
+		{"Empty strings use defaults", `{"draw": "", "start": "", "length": ""}`, 1, 0, 10},
+		{"Null values use defaults", `{"draw": null, "start": null}`, 1, 0, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := ParseParams(newJSONContext(tt.body))
+
+			if params.Draw != tt.draw || params.Start != tt.start || params.Length != tt.length {
+				t.Errorf("Expected draw=%d start=%d length=%d, got %+v", tt.draw, tt.start, tt.length, params)
+			}
+		})
+	}
+
+	t.Run("Non-numeric strings fall back to query", func(t *testing.T) {
+		c := newJSONContext(`{"draw": "two", "start": 20}`)
+		c.Request.URL.RawQuery = "draw=9"
+
+		if params := ParseParams(c); params.Draw != 9 || params.Start != 0 {
+			t.Errorf("Expected fallback to the query string, got %+v", params)
+		}
+	})
+}
+
+func TestParseOrders(t *testing.T) {
+	t.Run("Three entries with index resolution", func(t *testing.T) {
+		c, _ := newTestContext("order[0][column]=1&order[0][dir]=desc" +
+			"&order[1][column]=name&order[1][dir]=ASC" +
+			"&order[2][column]=0&order[2][dir]=bogus" +
+			"&columns[0][data]=id&columns[1][data]=email")
+
+		orders := ParseParams(c).Orders
+
+		expected := []dto.Order{
+			{Column: "email", Dir: "desc"},
+			{Column: "name", Dir: "asc"},
+			{Column: "id", Dir: "asc"},
+		}
+		if !reflect.DeepEqual(orders, expected) {
+			t.Errorf("Expected %v, got %v", expected, orders)
+		}
+	})
+
+	t.Run("Gaps and ordering of indices", func(t *testing.T) {
+		c, _ := newTestContext("order[5][column]=created_at&order[0][column]=name&order[2][dir]=desc")
+
+		orders := ParseParams(c).Orders
+
+		expected := []dto.Order{
+			{Column: "name", Dir: "asc"},
+			{Column: "created_at", Dir: "asc"},
+		}
+		if !reflect.DeepEqual(orders, expected) {
+			t.Errorf("Expected %v, got %v", expected, orders)
+		}
+	})
+
+	t.Run("No order keys", func(t *testing.T) {
+		c, _ := newTestContext("draw=1")
+
+		if orders := ParseParams(c).Orders; len(orders) != 0 {
+			t.Errorf("Expected no orders, got %v", orders)
+		}
+	})
+
+	t.Run("JSON body", func(t *testing.T) {
+		c := newJSONContext(`{"order": [{"column": 0, "dir": "desc"}, {"column": "name", "dir": "asc"}], "columns": [{"data": "id"}]}`)
+
+		params := ParseParams(c)
+
+		expected := []dto.Order{{Column: "id", Dir: "desc"}, {Column: "name", Dir: "asc"}}
+		if !reflect.DeepEqual(params.Orders, expected) {
+			t.Errorf("Expected %v, got %v", expected, params.Orders)
+		}
+	})
+}
+
+func TestParseParamsNegativeStart(t *testing.T) {
+	c, _ := newTestContext("start=-50&length=-1")
+
+	params := ParseParams(c)
+
+	if params.Start != 0 {
+		t.Errorf("Expected start=0, got %d", params.Start)
+	}
+	if params.Length != -1 {
+		t.Errorf("Expected length=-1 to be preserved, got %d", params.Length)
+	}
+}
+
+func TestParseParamsOrderColumnIndex(t *testing.T) {
+	// Shaped like a real DataTables request: the order column is always an index
+	query := "order[0][column]=1&order[0][dir]=desc" +
+		"&columns[0][data]=id&columns[0][name]=" +
+		"&columns[1][data]=&columns[1][name]=email"
+
+	c, _ := newTestContext(query)
+	params := ParseParams(c)
+
+	if params.Order != "email" || params.Dir != "desc" {
+		t.Errorf("Expected order email desc, got %q %q", params.Order, params.Dir)
+	}
+	expected := []dto.Order{{Column: "email", Dir: "desc"}}
+	if !reflect.DeepEqual(params.Orders, expected) {
+		t.Errorf("Expected %v, got %v", expected, params.Orders)
+	}
+
+	t.Run("Resolved column is ordered by", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext(query)
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, map[string]string{"email": "email"}, NewOptions()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if last := fake.LastSelect(); !strings.Contains(last.SQL, "ORDER BY email desc") {
+			t.Errorf("Expected ORDER BY email desc, got %s", last.SQL)
+		}
+	})
+
+	t.Run("Index without a column definition is used as is", func(t *testing.T) {
+		c, _ := newTestContext("order[0][column]=3")
+		if params := ParseParams(c); params.Order != "3" {
+			t.Errorf("Expected order %q, got %q", "3", params.Order)
+		}
+	})
+}
+
+func TestParseOrdersColumnNameFallback(t *testing.T) {
+	c, _ := newTestContext("order[0][column]=1&order[0][dir]=desc&order[1][column]=0" +
+		"&columns[0][data]=id&columns[0][name]=user_id" +
+		"&columns[1][data]=&columns[1][name]=full_name")
+
+	orders := ParseParams(c).Orders
+
+	expected := []dto.Order{
+		{Column: "full_name", Dir: "desc"},
+		{Column: "id", Dir: "asc"},
+	}
+	if !reflect.DeepEqual(orders, expected) {
+		t.Errorf("Expected %v, got %v", expected, orders)
+	}
+}
+
+func TestParseParamsColumnNameFallback(t *testing.T) {
+	t.Run("Query parameters", func(t *testing.T) {
+		c, _ := newTestContext("columns[0][data]=&columns[0][name]=full_name")
+
+		if params := ParseParams(c); params.Order != "full_name" {
+			t.Errorf("Expected order='full_name', got %q", params.Order)
+		}
+	})
+
+	t.Run("JSON body", func(t *testing.T) {
+		c := newJSONContext(`{
+			"order": [{"column": 0, "dir": "desc"}],
+			"columns": [{"data": "", "name": "full_name"}]
+		}`)
+
+		if params := ParseParams(c); params.Order != "full_name" {
+			t.Errorf("Expected order='full_name', got %q", params.Order)
+		}
+	})
+}
+
+func TestParseParamsPagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		rawQuery      string
+		opts          Options
+		start, length int
+	}{
+		{"DataTables start/length", "start=40&length=20", NewOptions(), 40, 20},
+		{"Page and per_page", "page=3&per_page=20", NewOptions(), 40, 20},
+		{"Page only keeps the default length", "page=2", NewOptions(), 10, 10},
+		{"Invalid page is the first page", "page=0&per_page=25", NewOptions(), 0, 25},
+		{"Start/length take precedence", "start=5&page=3&per_page=20", NewOptions(), 5, 10},
+		{"Custom names", "p=2&limit=15", NewOptions().WithPageParams("p", "limit"), 15, 15},
+		{"Default names ignored with custom names", "page=2&per_page=15", NewOptions().WithPageParams("p", "limit"), 0, 10},
+		{"per_page capped", "page=2&per_page=1000", NewOptions(), 500, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(tt.rawQuery)
+
+			params := parseParams(c, tt.opts)
+
+			if params.Start != tt.start || params.Length != tt.length {
+				t.Errorf("Expected start=%d length=%d, got start=%d length=%d",
+					tt.start, tt.length, params.Start, params.Length)
+			}
+		})
+	}
+}
+
+// newFormContext builds a Gin context for a form POST with the given body and raw query string.
+func newFormContext(body, rawQuery string) *gin.Context {
+	c, _ := newTestContext("")
+	c.Request = httptest.NewRequest(http.MethodPost, "/?"+rawQuery, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c
+}
+
+func TestParseParamsSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source ParamSource
+		// expected values for the form request (body draw=2, search=body; query draw=3, start=20)
+		draw   int64
+		start  int
+		search string
+	}{
+		{"Both prefers the query", ParamSourceBoth, 3, 20, "body"},
+		{"Query ignores the body", ParamSourceQuery, 3, 20, ""},
+		{"Form ignores the query", ParamSourceForm, 2, 0, "body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFormContext("draw=2&search%5Bvalue%5D=body", "draw=3&start=20")
+
+			params := parseParams(c, NewOptions().WithParamSource(tt.source))
+
+			if params.Draw != tt.draw || params.Start != tt.start || params.Search != tt.search {
+				t.Errorf("Expected draw=%d start=%d search=%q, got draw=%d start=%d search=%q",
+					tt.draw, tt.start, tt.search, params.Draw, params.Start, params.Search)
+			}
+		})
+	}
+
+	t.Run("Form body remains readable through PostForm", func(t *testing.T) {
+		c := newFormContext("draw=2&custom=x", "")
+
+		parseParams(c, NewOptions())
+
+		if v := c.PostForm("custom"); v != "x" {
+			t.Errorf("Expected custom='x', got %q", v)
+		}
+	})
+
+	t.Run("Form orders", func(t *testing.T) {
+		c := newFormContext("order%5B0%5D%5Bcolumn%5D=1&order%5B0%5D%5Bdir%5D=desc&columns%5B1%5D%5Bdata%5D=email", "")
+
+		params := parseParams(c, NewOptions().WithParamSource(ParamSourceForm))
+
+		expected := []dto.Order{{Column: "email", Dir: "desc"}}
+		if !reflect.DeepEqual(params.Orders, expected) {
+			t.Errorf("Expected %v, got %v", expected, params.Orders)
+		}
+	})
+
+	t.Run("JSON body", func(t *testing.T) {
+		newContext := func() *gin.Context {
+			c := newJSONContext(`{"draw": 4}`)
+			c.Request.URL.RawQuery = "draw=9"
+			return c
+		}
+
+		if params := parseParams(newContext(), NewOptions().WithParamSource(ParamSourceForm)); params.Draw != 4 {
+			t.Errorf("Expected body draw=4 with ParamSourceForm, got %d", params.Draw)
+		}
+		if params := parseParams(newContext(), NewOptions().WithParamSource(ParamSourceQuery)); params.Draw != 9 {
+			t.Errorf("Expected query draw=9 with ParamSourceQuery, got %d", params.Draw)
+		}
+	})
+}
+
+func TestParseParamsColumnSearches(t *testing.T) {
+	expected := []dto.ColumnSearch{{Column: "price", Value: ">100"}, {Column: "full_name", Value: "jo"}}
+
+	t.Run("Query parameters", func(t *testing.T) {
+		c, _ := newTestContext("columns[0][data]=id&columns[0][search][value]=" +
+			"&columns[2][data]=&columns[2][name]=full_name&columns[2][search][value]=jo" +
+			"&columns[1][data]=price&columns[1][search][value]=%3E100")
+
+		if params := ParseParams(c); !reflect.DeepEqual(params.ColumnSearches, expected) {
+			t.Errorf("Expected %v, got %v", expected, params.ColumnSearches)
+		}
+	})
+
+	t.Run("JSON body", func(t *testing.T) {
+		c := newJSONContext(`{"columns": [
+			{"data": "id", "search": {"value": ""}},
+			{"data": "price", "search": {"value": ">100"}},
+			{"data": "", "name": "full_name", "search": {"value": "jo"}}
+		]}`)
+
+		if params := ParseParams(c); !reflect.DeepEqual(params.ColumnSearches, expected) {
+			t.Errorf("Expected %v, got %v", expected, params.ColumnSearches)
+		}
+	})
+}
+
+func TestNormalizeDir(t *testing.T) {
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{"desc", "desc"},
+		{"DESC ", "desc"},
+		{" desc", "desc"},
+		{"Desc", "desc"},
+		{"\tASC\n", "asc"},
+		{"de sc", "asc"},
+		{"", "asc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if dir := normalizeDir(tt.dir); dir != tt.expected {
+				t.Errorf("normalizeDir(%q) = %q, want %q", tt.dir, dir, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Query parameters", func(t *testing.T) {
+		c, _ := newTestContext("order[0][column]=name&order[0][dir]=DESC%20&order[1][column]=id&order[1][dir]=%20Desc")
+
+		params := ParseParams(c)
+
+		expected := []dto.Order{{Column: "name", Dir: "desc"}, {Column: "id", Dir: "desc"}}
+		if params.Dir != "desc" || !reflect.DeepEqual(params.Orders, expected) {
+			t.Errorf("Expected desc directions, got %q and %v", params.Dir, params.Orders)
+		}
+	})
+}
+
+func TestParseParamsPrefix(t *testing.T) {
+	rawQuery := "draw=1&search[value]=other&order[0][column]=id" +
+		"&table1_draw=5&table1_start=20&table1_length=10&table1_search[value]=john" +
+		"&table1_order[0][column]=email&table1_order[0][dir]=desc&table1_order[1][column]=0&table1_columns[0][data]=id"
+
+	t.Run("Prefixed parameters", func(t *testing.T) {
+		c, _ := newTestContext(rawQuery)
+
+		params := parseParams(c, NewOptions().WithSearchPrefix("table1_"))
+
+		if params.Draw != 5 || params.Start != 20 || params.Search != "john" {
+			t.Errorf("Unexpected params: %+v", params)
+		}
+		if params.Order != "email" || params.Dir != "desc" {
+			t.Errorf("Expected order email desc, got %q %q", params.Order, params.Dir)
+		}
+		expected := []dto.Order{{Column: "email", Dir: "desc"}, {Column: "id", Dir: "asc"}}
+		if !reflect.DeepEqual(params.Orders, expected) {
+			t.Errorf("Expected %v, got %v", expected, params.Orders)
+		}
+	})
+
+	t.Run("Prefixed page parameters", func(t *testing.T) {
+		c, _ := newTestContext("page=9&t2_page=3&t2_per_page=20")
+
+		params := parseParams(c, NewOptions().WithSearchPrefix("t2_"))
+
+		if params.Start != 40 || params.Length != 20 {
+			t.Errorf("Expected start=40 length=20, got %+v", params)
+		}
+	})
+
+	t.Run("No prefix reads the standard names", func(t *testing.T) {
+		c, _ := newTestContext(rawQuery)
+
+		params := parseParams(c, NewOptions())
+
+		if params.Draw != 1 || params.Search != "other" || params.Order != "id" {
+			t.Errorf("Unexpected params: %+v", params)
+		}
+	})
+}
+
+func TestParseParamsCursor(t *testing.T) {
+	c, _ := newTestContext("cursor=abc-123")
+	if params := ParseParams(c); params.Cursor != "abc-123" {
+		t.Errorf("Expected cursor from the query string, got %q", params.Cursor)
+	}
+
+	for body, expected := range map[string]string{`{"cursor": 1250}`: "1250", `{"cursor": "2024-01-01"}`: "2024-01-01", `{"cursor": null}`: ""} {
+		if params := ParseParams(newJSONContext(body)); params.Cursor != expected {
+			t.Errorf("Body %s: expected cursor %q, got %q", body, expected, params.Cursor)
+		}
+	}
+}
+
+func TestParseParamsColumns(t *testing.T) {
+	c, _ := newTestContext("columns[1][data]=0&columns[0][data]=2&columns[2][data]=&columns[2][name]=email")
+	if params := ParseParams(c); strings.Join(params.Columns, ",") != "2,0,email" {
+		t.Errorf("Expected columns 2,0,email from the query string, got %v", params.Columns)
+	}
+
+	params := ParseParams(newJSONContext(`{"columns": [{"data": 2}, {"data": "0"}, {"data": null, "name": "email"}], "order": [{"column": 0, "dir": "desc"}]}`))
+	if strings.Join(params.Columns, ",") != "2,0,email" {
+		t.Errorf("Expected columns 2,0,email from the JSON body, got %v", params.Columns)
+	}
+	if params.Order != "2" || params.Dir != "desc" {
+		t.Errorf("Expected the JSON body to be decoded with numeric data, got order %q %q", params.Order, params.Dir)
+	}
+}
@@ -1,9 +1,19 @@
 package datatables
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 // OfReturn executes the core DataTables server-side logic.
@@ -43,91 +53,675 @@ func OfReturn[T any](
 	orderable map[string]string,
 	opts Options,
 ) (dto.Datatables, error) {
-	// Validate column names to prevent SQL injection
-	if err := validateSearchableColumns(searchable); err != nil {
-		return dto.Datatables{}, err
+	// Parse DataTables request parameters
+	params := parseParams(c, opts)
+
+	// Let the response helpers skip HTML escaping
+	if opts.PureJSON {
+		c.Set(pureJSONKey, true)
+	}
+
+	return process(c, query, dest, params, searchable, orderable, opts)
+}
+
+// OfParams executes the same DataTables server-side logic as OfReturn using
+// already-parsed parameters, without a Gin context. This suits server-to-server
+// calls, background jobs, and tests.
+//
+// The params are normalized like parsed request parameters (direction
+// validation, offset clamping, and the default page size limit).
+// The query's context (set with query.WithContext) bounds the database calls.
+// Options that need the request, such as WithMaxPageSizeFunc, are ignored.
+//
+// Example:
+//   params := dto.NewParams().WithSearch("john").WithPage(2, 25).WithOrder("name", "desc").Build()
+//   result, err := datatables.OfParams(db.Model(&User{}), &users, params, searchable, orderable, opts)
+func OfParams[T any](
+	query *gorm.DB,
+	dest *[]T,
+	params dto.Params,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+) (dto.Datatables, error) {
+	return process(nil, query, dest, normalizeParams(params, maxPageSize(nil, opts)), searchable, orderable, opts)
+}
+
+// process implements OfReturn and OfParams. The Gin context may be nil.
+func process[T any](
+	c *gin.Context,
+	query *gorm.DB,
+	dest *[]T,
+	params dto.Params,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+) (dto.Datatables, error) {
+	// Resolve and validate the searchable and orderable columns
+	model := query.Statement.Model
+	if model == nil {
+		model = dest
 	}
-	if err := validateOrderableColumns(orderable); err != nil {
+	req, err := resolveRequest(c, query, model, params, searchable, orderable, opts)
+	if err != nil {
 		return dto.Datatables{}, err
 	}
+	params, searchable, opts = req.params, req.searchable, req.opts
 
-	// Parse DataTables request parameters
-	params := ParseParams(c)
+	// Bound all database calls by the query timeout
+	if opts.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		query, cancel = withQueryTimeout(c, query, opts.QueryTimeout)
+		defer cancel()
+	}
 
 	// Count total records (before filtering)
 	var total int64
-	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+	totalQuery, groupedTotal := query, opts.GroupedCount
+	if opts.AbsoluteTotalModel != nil {
+		// Count the whole table, ignoring the base query's conditions
+		totalQuery, groupedTotal = query.Session(&gorm.Session{NewDB: true}).Model(opts.AbsoluteTotalModel), false
+	}
+	if opts.TotalDB != nil {
+		// Count on a separate handle, such as a read replica
+		totalQuery = onHandle(totalQuery, opts.TotalDB)
+	}
+	var timings dto.Timings
+	started := time.Now()
+	if opts.TotalProvider != nil {
+		// The total comes from a cached or estimated source
+		if total, err = opts.TotalProvider(); err != nil {
+			return dto.Datatables{}, err
+		}
+	} else if err := withRetry(query.Statement.Context, opts, func() error {
+		return countRecords(totalQuery, groupedTotal, &total)
+	}); err != nil {
+		return queryFailure(params, opts, err)
+	}
+	timings.TotalCount = durationMillis(time.Since(started))
+
+	// Apply the search, per-column searches, declarative filters, and HAVING
+	filteredQuery, filterApplied := req.filter(c, query, false)
+
+	// Count filtered records (after search, before pagination).
+	// Without any filtering the filtered count equals the total, so the
+	// redundant query is skipped (unless the total counts a different query
+	// or comes from a provider, which may be stale).
+	var filtered int64
+	switch {
+	case req.inMemorySearch:
+		// Computed after fetching
+	case !filterApplied && opts.AbsoluteTotalModel == nil && opts.TotalProvider == nil:
+		filtered = total
+	default:
+		started = time.Now()
+		if err := withRetry(query.Statement.Context, opts, func() error {
+			if opts.CountStrategy != nil {
+				var err error
+				filtered, err = opts.CountStrategy.Count(filteredQuery.Session(&gorm.Session{}))
+				return err
+			}
+			return countRecords(filteredQuery, opts.GroupedCount, &filtered)
+		}); err != nil {
+			return queryFailure(params, opts, err)
+		}
+		timings.FilteredCount = durationMillis(time.Since(started))
+	}
+
+	// Aggregate the filtered set for the footer
+	var footer map[string]interface{}
+	if len(opts.FooterAggregates) > 0 {
+		if err := withRetry(query.Statement.Context, opts, func() error {
+			var err error
+			footer, err = footerAggregates(filteredQuery, opts.FooterAggregates, opts.GroupedCount)
+			return err
+		}); err != nil {
+			return queryFailure(params, opts, err)
+		}
+	}
+
+	// A filtered count above the total means the query multiplies rows
+	// (a provided total may simply be stale)
+	if filtered > total && opts.TotalProvider == nil {
+		mismatch := &CountMismatchError{Total: total, Filtered: filtered}
+		if opts.StrictCounts {
+			return dto.Datatables{}, mismatch
+		}
+		warnf(opts, "%s", mismatch.Error())
+	}
+
+	// Apply ordering, pagination, and computed columns
+	if filteredQuery, err = req.page(filteredQuery); err != nil {
 		return dto.Datatables{}, err
 	}
 
-	// Apply filtering (global search)
-	filteredQuery := query.Session(&gorm.Session{})
-	if params.Search != "" && len(searchable) > 0 {
-		filteredQuery = applySearch(filteredQuery, searchable, params.Search)
+	// Fetch results from database; length=0 requests the counts only
+	fetchQuery := filteredQuery
+	if opts.FetchDB != nil {
+		// Fetch on a separate handle, such as the primary
+		fetchQuery = onHandle(filteredQuery, opts.FetchDB)
 	}
+	started = time.Now()
+	if params.Length == 0 && !req.fetchAll {
+		*dest = []T{}
+	} else if err := withRetry(query.Statement.Context, opts, func() error {
+		return fetchQuery.Session(&gorm.Session{}).Find(dest).Error
+	}); err != nil {
+		return queryFailure(params, opts, err)
+	}
+	timings.Fetch = durationMillis(time.Since(started))
 
-	// Count filtered records (after search, before pagination)
-	var filtered int64
-	if err := filteredQuery.Count(&filtered).Error; err != nil {
+	// Convert the typed results into rows
+	rows, err := fetchedRows(dest, opts)
+	if err != nil {
 		return dto.Datatables{}, err
 	}
 
-	// Apply ordering
-	filteredQuery = applyOrdering(filteredQuery, params, orderable, opts.DefaultOrder)
+	// Filter, sort, and paginate the fetched set in Go
+	if req.inMemorySearch {
+		rows = filterRows(rows, searchRowKeys(searchable, opts.SearchableMap), params.Search, opts.UnaccentSearch)
+		filtered = int64(len(rows))
+	}
+	if req.computedOrder != "" {
+		sortByComputed(rows, req.computedOrder, params.Dir, opts)
+	}
+	if req.fetchAll {
+		rows = paginateRows(rows, params.Start, params.Length)
+	}
 
-	// Apply pagination
-	if params.Length > 0 {
-		filteredQuery = filteredQuery.Offset(params.Start).Limit(params.Length)
+	// Remember where the next keyset page starts
+	var cursor interface{}
+	if opts.CursorColumn != "" {
+		key := resolveCursorKey(query, dest, opts.CursorColumn)
+		if cursor, err = nextCursor(rows, opts.CursorColumn, key, params.Length); err != nil {
+			return dto.Datatables{}, err
+		}
 	}
 
-	// Fetch results from database
-	if err := filteredQuery.Find(dest).Error; err != nil {
-		return dto.Datatables{}, err
+	return buildResponse(c, rows, params, searchable, opts, pageSummary{
+		total:           total,
+		filtered:        filtered,
+		requestedLength: req.requestedLength,
+		cursor:          cursor,
+		timings:         timings,
+		footer:          footer,
+	})
+}
+
+// fetchedRows runs the FetchHook on the typed results and converts them into
+// rows, normalizing numbers and flattening collections when configured.
+func fetchedRows[T any](dest *[]T, opts Options) ([]map[string]interface{}, error) {
+	// Let the caller enrich the typed results
+	if opts.FetchHook != nil {
+		if err := opts.FetchHook(dest); err != nil {
+			return nil, err
+		}
 	}
 
 	// Convert struct slice to []map[string]interface{}
 	rows := structToMapSlice(dest)
 
+	// Give struct rows the same number types as scanned map rows
+	if opts.NormalizeNumbers {
+		normalizeNumbers(rows)
+	}
+
+	// Flatten slice/map fields into display-friendly strings
+	if opts.FlattenCollections {
+		flattenCollections(rows, opts.CollectionSeparator)
+	}
+
+	return rows, nil
+}
+
+// pageSummary is what the response reports besides the page rows.
+type pageSummary struct {
+	total           int64
+	filtered        int64
+	requestedLength int // Page length requested before the result limit
+	cursor          interface{}
+	timings         dto.Timings
+	footer          map[string]interface{}
+}
+
+// buildResponse turns the current page into the DataTables response: it drops
+// the rows failing RowFilter, applies the row options and page hooks, shapes
+// the output (array data, key case, fields), and fills in the response fields.
+func buildResponse(c *gin.Context, rows []map[string]interface{}, params dto.Params, searchable []string, opts Options, page pageSummary) (dto.Datatables, error) {
+	var err error
+	filtered := page.filtered
+
+	// Drop rows failing the Go-side predicate
+	var dropped int
+	if opts.RowFilter != nil {
+		kept := rows[:0:0]
+		for _, row := range rows {
+			if opts.RowFilter(row) {
+				kept = append(kept, row)
+			}
+		}
+		dropped = len(rows) - len(kept)
+		rows = kept
+	}
+
+	// Annotate search matches on the current page
+	if opts.MatchHighlight && params.Search != "" {
+		annotateMatches(rows, searchRowKeys(searchable, opts.SearchableMap), params.Search)
+	}
+
 	// Apply DataTables options (add/edit/remove columns, indexes)
-	rows = applyOptions(rows, opts, params.Start)
+	rows = applyOptions(c, rows, opts, params.Start)
+
+	// Let the caller decorate the current page
+	if opts.PagePostProcess != nil {
+		if rows, err = opts.PagePostProcess(rows); err != nil {
+			return dto.Datatables{}, err
+		}
+	}
+
+	// Append subtotal and grand total rows
+	if opts.GroupTotalColumn != "" {
+		rows = appendGroupTotals(rows, opts.GroupTotalColumn, opts.GroupTotalSums)
+	}
+
+	// DataTables clients expect an array, never null
+	if rows == nil {
+		rows = []map[string]interface{}{}
+	}
+
+	// Project rows into DataTables' array data source format
+	var data interface{} = rows
+	if len(opts.ArrayDataColumns) > 0 {
+		data = projectRows(rows, clientArrayColumns(opts.ArrayDataColumns, params.Columns))
+	}
+
+	// Normalize output key casing and trim rows to the requested fields
+	if len(opts.ArrayDataColumns) == 0 {
+		applyKeyCase(rows, opts.KeyCase, opts.IndexColumn)
+		if c != nil && opts.FieldsParam != "" {
+			selectFields(rows, c.Query(opts.FieldsParam), opts.IndexColumn)
+		}
+	}
+
+	// Tell the client why the table is empty
+	var message string
+	if filtered == 0 {
+		message = opts.EmptyMessage
+	}
+
+	// Flag results cut short by the result limit
+	truncated := params.Length != page.requestedLength && filtered > int64(params.Start)+int64(params.Length)
 
 	return dto.Datatables{
 		Draw:            params.Draw,
-		RecordsTotal:    total,
+		RecordsTotal:    page.total,
 		RecordsFiltered: filtered,
-		Data:            rows,
+		Data:            data,
+		Message:         message,
+		RecordsDropped:  dropped,
+		NextCursor:      page.cursor,
+		Timings:         responseTimings(page.timings, opts),
+		Truncated:       truncated,
+		Footer:          page.footer,
 	}, nil
 }
 
-// applySearch adds global search conditions to the query.
-// Uses OR conditions across all searchable columns with case-insensitive matching.
-func applySearch(query *gorm.DB, searchable []string, searchValue string) *gorm.DB {
-	for i, col := range searchable {
-		searchPattern := "%" + searchValue + "%"
-		if i == 0 {
-			query = query.Where("LOWER("+col+") LIKE LOWER(?)", searchPattern)
-		} else {
-			query = query.Or("LOWER("+col+") LIKE LOWER(?)", searchPattern)
+// responseTimings returns the timings to attach to the response when
+// opts.Timings is set, or nil.
+func responseTimings(timings dto.Timings, opts Options) *dto.Timings {
+	if !opts.Timings {
+		return nil
+	}
+	return &timings
+}
+
+// durationMillis converts a duration to fractional milliseconds.
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// resolveColumns resolves the final searchable columns (AutoSearchable from the
+// model, SearchFallback, SearchableHidden) and validates all column names used in SQL
+// to prevent SQL injection.
+func resolveColumns(query *gorm.DB, model interface{}, searchable []string, orderable map[string]string, opts Options) ([]string, error) {
+	// Resolve searchable columns from the model when requested
+	if opts.AutoSearchable && len(searchable) == 0 {
+		columns, err := stringColumnsFromModel(query, model)
+		if err != nil {
+			return nil, err
 		}
+		searchable = columns
 	}
-	return query
+
+	// Fall back to the orderable database columns when requested
+	if opts.SearchFallback && len(searchable) == 0 {
+		for _, key := range sortedKeys(orderable) {
+			if col := orderable[key]; !containsString(searchable, col) {
+				searchable = append(searchable, col)
+			}
+		}
+	}
+
+	// Hidden, mapped, and boolean searchable columns are searched like the others
+	extra := append([]string(nil), opts.SearchableHidden...)
+	for _, key := range sortedKeys(opts.SearchableMap) {
+		extra = append(extra, opts.SearchableMap[key])
+	}
+	boolColumns := make([]string, 0, len(opts.BoolSearchColumns))
+	for col := range opts.BoolSearchColumns {
+		boolColumns = append(boolColumns, col)
+	}
+	sort.Strings(boolColumns)
+	extra = append(extra, boolColumns...)
+	for _, col := range extra {
+		if !containsString(searchable, col) {
+			searchable = append(append([]string(nil), searchable...), col)
+		}
+	}
+
+	// Validate column names to prevent SQL injection
+	if err := validateSearchableColumnsWith(searchable, opts.SearchableValidator); err != nil {
+		return nil, err
+	}
+	if err := validateOrderableColumnsWith(orderable, opts.OrderableValidator); err != nil {
+		return nil, err
+	}
+	if err := validateSearchableMapWith(opts.SearchableMap, opts.SearchableValidator); err != nil {
+		return nil, err
+	}
+	if err := validateSearchNormalizers(opts.SearchNormalizers); err != nil {
+		return nil, err
+	}
+	if err := validateComputedColumns(opts.ComputedColumns); err != nil {
+		return nil, err
+	}
+	if err := validateParamFilters(opts.ParamFilters); err != nil {
+		return nil, err
+	}
+	if err := validateFooterAggregates(opts.FooterAggregates); err != nil {
+		return nil, err
+	}
+	if opts.CursorColumn != "" && !isValidColumnName(opts.CursorColumn) {
+		return nil, &ValidationError{
+			Field:   opts.CursorColumn,
+			Message: "cursor column name contains invalid characters" + invalidColumnDetail(opts.CursorColumn),
+		}
+	}
+	if opts.StableSortColumn != "" && !isValidColumnName(opts.StableSortColumn) {
+		return nil, &ValidationError{
+			Field:   opts.StableSortColumn,
+			Message: "stable sort column name contains invalid characters" + invalidColumnDetail(opts.StableSortColumn),
+		}
+	}
+
+	return searchable, nil
+}
+
+// resolveSearch falls back to opts.InitialSearch when the client sends no
+// search value, and enforces the search length cap (see limitSearch).
+func resolveSearch(value string, opts Options) (string, error) {
+	if value == "" {
+		value = opts.InitialSearch
+	}
+	return limitSearch(value, opts)
+}
+
+// limitResult caps the page length at opts.ResultLimit. All records
+// (length=-1) and longer pages are capped; 0 (counts only) is kept.
+func limitResult(length int, opts Options) int {
+	if opts.ResultLimit <= 0 || length == 0 {
+		return length
+	}
+	if length < 0 || length > opts.ResultLimit {
+		return opts.ResultLimit
+	}
+	return length
+}
+
+// cursorValue converts a cursor request value to an integer when it is one,
+// so numeric cursor columns are compared as numbers.
+func cursorValue(cursor string) interface{} {
+	if n, err := strconv.ParseInt(cursor, 10, 64); err == nil {
+		return n
+	}
+	return cursor
+}
+
+// cursorKey locates the cursor column in the output rows: the row key of
+// its struct field (named like the converter does, from the JSON tag or field
+// name) and, for a field of an embedded struct such as gorm.Model's ID, the
+// field's index inside the embedded value.
+type cursorKey struct {
+	key   string
+	index []int
+}
+
+// resolveCursorKey resolves the cursor column through the schema of the
+// fetched rows. Rows scanned into maps are keyed by the unqualified column.
+func resolveCursorKey(query *gorm.DB, dest interface{}, column string) cursorKey {
+	column = column[strings.LastIndex(column, ".")+1:]
+	rowSchema, err := schema.Parse(dest, &sync.Map{}, query.NamingStrategy)
+	if err != nil {
+		return cursorKey{key: column}
+	}
+
+	field := rowSchema.LookUpField(column)
+	if field == nil || len(field.StructField.Index) == 0 {
+		return cursorKey{key: column}
+	}
+	top := rowSchema.ModelType.Field(field.StructField.Index[0])
+	return cursorKey{key: getFieldName(top), index: field.StructField.Index[1:]}
+}
+
+// nextCursor returns the cursor of the page after rows: the cursor column's
+// value in the last row. Returns nil when the page is not full, as no rows
+// remain, and a ValidationError when the rows do not hold the cursor column
+// (e.g. its field is tagged json:"-"), since pagination could not continue.
+func nextCursor(rows []map[string]interface{}, column string, key cursorKey, length int) (interface{}, error) {
+	if length <= 0 || len(rows) < length {
+		return nil, nil
+	}
+
+	value, ok := rows[len(rows)-1][key.key]
+	if ok && len(key.index) > 0 {
+		value, ok = embeddedValue(value, key.index)
+	}
+	if !ok {
+		return nil, &ValidationError{
+			Field:   column,
+			Message: "cursor column is not present in the fetched rows",
+		}
+	}
+	return value, nil
+}
+
+// embeddedValue returns the field at index inside an embedded struct value.
+// Returns false if the value is not a struct or a nil pointer is on the path.
+func embeddedValue(value interface{}, index []int) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	field, err := v.FieldByIndexErr(index)
+	if err != nil || !field.CanInterface() {
+		return nil, false
+	}
+	return field.Interface(), true
+}
+
+// limitOffset enforces opts.MaxOffset on the start offset, either clamping
+// it or returning a ValidationError when opts.RejectDeepOffset is set.
+func limitOffset(start int, opts Options) (int, error) {
+	if opts.MaxOffset <= 0 || start <= opts.MaxOffset {
+		return start, nil
+	}
+
+	if opts.RejectDeepOffset {
+		return 0, &ValidationError{
+			Field:   "start",
+			Message: "start offset exceeds the maximum of " + strconv.Itoa(opts.MaxOffset),
+		}
+	}
+	return opts.MaxOffset, nil
+}
+
+// applyPage applies ordering, pagination (when paginate is set), and the
+// database-computed columns to the fetch query.
+func applyPage(query *gorm.DB, model interface{}, params dto.Params, orderable map[string]string, opts Options, paginate bool) (*gorm.DB, error) {
+	if opts.CursorColumn != "" && paginate {
+		// Keyset pagination: the rows after the last-seen cursor value
+		query = query.Order(opts.CursorColumn + " ASC")
+		if params.Cursor != "" {
+			query = query.Where(opts.CursorColumn+" > ?", cursorValue(params.Cursor))
+		}
+		if params.Length > 0 {
+			query = query.Limit(params.Length)
+		}
+	} else {
+		query = applyOrdering(query, params, orderable, opts)
+
+		if paginate && params.Length > 0 {
+			query = query.Offset(params.Start).Limit(params.Length)
+		}
+	}
+
+	// Computed columns are only needed for the fetch, not the counts
+	if len(opts.ComputedColumns) > 0 {
+		return applyComputedColumns(query, model, opts.ComputedColumns)
+	}
+	return query, nil
+}
+
+// searchRowKeys returns the row keys of the searchable columns, using the
+// frontend names of mapped columns (e.g. "roles.name" -> "role").
+func searchRowKeys(searchable []string, mapped map[string]string) []string {
+	if len(mapped) == 0 {
+		return searchable
+	}
+
+	keys := make([]string, 0, len(searchable))
+	for _, col := range searchable {
+		key := col
+		for _, name := range sortedKeys(mapped) {
+			if mapped[name] == col {
+				key = name
+				break
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// maxPageSize resolves the page size limit for the request, using
+// opts.MaxPageSizeFunc when set and returning a positive value.
+func maxPageSize(c *gin.Context, opts Options) int {
+	if opts.MaxPageSizeFunc != nil && c != nil {
+		if n := opts.MaxPageSizeFunc(c); n > 0 {
+			return n
+		}
+	}
+	return defaultMaxPageSize
+}
+
+// withQueryTimeout bounds the query's calls by timeout. The deadline is
+// derived from the query's context, keeping any deadline or cancellation the
+// caller set with query.WithContext; cancelling the request cancels the calls
+// too. The returned function releases the context and must be called.
+func withQueryTimeout(c *gin.Context, query *gorm.DB, timeout time.Duration) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(queryContext(query), timeout)
+	if c != nil && c.Request != nil {
+		stop := context.AfterFunc(c.Request.Context(), cancel)
+		return query.WithContext(ctx), func() {
+			stop()
+			cancel()
+		}
+	}
+	return query.WithContext(ctx), cancel
+}
+
+// queryContext returns the query's context, or context.Background when none is set.
+func queryContext(query *gorm.DB) context.Context {
+	if query.Statement.Context != nil {
+		return query.Statement.Context
+	}
+	return context.Background()
+}
+
+// queryFailure converts a database error into the OfReturn result.
+// When a query timeout is configured and was exceeded, a well-formed DataTables
+// response carrying an inline error message is returned instead of the error,
+// so the table can display "query timed out" rather than failing with a 500.
+func queryFailure(params dto.Params, opts Options, err error) (dto.Datatables, error) {
+	if opts.QueryTimeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return dto.Datatables{
+			Draw:  params.Draw,
+			Data:  []map[string]interface{}{},
+			Error: ErrQueryTimeout.Error(),
+		}, nil
+	}
+	return dto.Datatables{}, err
 }
 
 // applyOrdering adds ORDER BY clause to the query.
 // Uses the orderable map to translate frontend column names to database columns.
-// Falls back to defaultOrder if no order is specified.
-func applyOrdering(query *gorm.DB, params dto.Params, orderable map[string]string, defaultOrder string) *gorm.DB {
-	if params.Order != "" {
+// Falls back to opts.DefaultOrder if no order is specified.
+// The client order is ignored entirely when opts.OrderingDisabled is set.
+//
+// When opts.StableSortColumn is set, it is appended as a final tiebreaker
+// unless it is already part of the ordering, so pagination stays stable
+// when ordering by non-unique columns.
+func applyOrdering(query *gorm.DB, params dto.Params, orderable map[string]string, opts Options) *gorm.DB {
+	var ordered []string
+
+	if params.Order != "" && !opts.OrderingDisabled {
 		// Check if the requested column is in the orderable map
 		if col, ok := orderable[params.Order]; ok {
-			return query.Order(col + " " + params.Dir)
+			// Re-validate the direction so the function is safe with arbitrary Params
+			query = query.Order(col + " " + normalizeDir(params.Dir))
+			ordered = append(ordered, col)
 		}
 	}
 
 	// Apply default ordering if specified and no valid order was provided
-	if defaultOrder != "" {
-		return query.Order(defaultOrder)
+	if len(ordered) == 0 && opts.DefaultOrder != "" {
+		query = query.Order(opts.DefaultOrder)
+		ordered = orderColumns(opts.DefaultOrder)
+	}
+
+	// Append the tiebreaker column for stable pagination
+	if opts.StableSortColumn != "" && !containsString(ordered, opts.StableSortColumn) {
+		query = query.Order(opts.StableSortColumn)
 	}
 
 	return query
 }
+
+// orderColumns extracts the column names from an ORDER BY clause.
+// e.g., "status DESC, id ASC" -> ["status", "id"]
+func orderColumns(order string) []string {
+	var columns []string
+	for _, term := range strings.Split(order, ",") {
+		if fields := strings.Fields(term); len(fields) > 0 {
+			columns = append(columns, fields[0])
+		}
+	}
+	return columns
+}
+
+// containsString reports whether the slice contains the given value.
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1113 @@
+package datatables
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TestOfReturn(t *testing.T) {
+	fake := &fakeDB{
+		count:  2,
+		result: userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"}, TestUser{ID: 2, Name: "Jane", Email: "jane@example.com"}),
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("draw=3&start=0&length=10")
+
+	var users []TestUser
+	result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, map[string]string{"name": "name"}, NewOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Draw != 3 {
+		t.Errorf("Expected draw=3, got %d", result.Draw)
+	}
+	if result.RecordsTotal != 2 || result.RecordsFiltered != 2 {
+		t.Errorf("Expected total=2 filtered=2, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if rows[1]["name"] != "Jane" || rows[1]["DT_RowIndex"] != 2 {
+		t.Errorf("Unexpected second row: %v", rows[1])
+	}
+}
+
+func TestOfReturnAutoSearchable(t *testing.T) {
+	t.Run("Searches string columns when enabled", func(t *testing.T) {
+		fake := &fakeDB{}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var customers []TestCustomer
+		opts := NewOptions().WithAutoSearchable(true)
+		if _, err := OfReturn(c, db.Model(&TestCustomer{}), &customers, nil, nil, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		sql := fake.LastSelect().SQL
+		for _, col := range []string{"LOWER(first_name)", "LOWER(nickname)", "LOWER(email_address)"} {
+			if !strings.Contains(sql, col) {
+				t.Errorf("Expected query to search %s, got %s", col, sql)
+			}
+		}
+		if strings.Contains(sql, "LOWER(age)") {
+			t.Errorf("Non-string column should not be searched: %s", sql)
+		}
+	})
+
+	t.Run("Empty searchable disables search by default", func(t *testing.T) {
+		fake := &fakeDB{}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var customers []TestCustomer
+		if _, err := OfReturn(c, db.Model(&TestCustomer{}), &customers, nil, nil, NewOptions()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if sql := fake.LastSelect().SQL; strings.Contains(sql, "LIKE") {
+			t.Errorf("Expected no search conditions, got %s", sql)
+		}
+	})
+}
+
+func TestApplyOrderingStableSort(t *testing.T) {
+	db := newDryRunDB(t)
+	orderable := map[string]string{"status": "status", "id": "id"}
+
+	t.Run("Appends tiebreaker after client order", func(t *testing.T) {
+		params := dto.Params{Order: "status", Dir: "desc"}
+		opts := NewOptions().WithStableSort("id")
+
+		sql := dryRunSQL(applyOrdering(db.Model(&TestUser{}), params, orderable, opts))
+		if !strings.HasSuffix(sql, "ORDER BY status desc,id") {
+			t.Errorf("Expected tiebreaker to be appended, got %s", sql)
+		}
+	})
+
+	t.Run("Appends tiebreaker after default order", func(t *testing.T) {
+		opts := NewOptions().WithDefaultOrder("status ASC").WithStableSort("id")
+
+		sql := dryRunSQL(applyOrdering(db.Model(&TestUser{}), dto.Params{}, orderable, opts))
+		if !strings.HasSuffix(sql, "ORDER BY status ASC,id") {
+			t.Errorf("Expected tiebreaker to be appended, got %s", sql)
+		}
+	})
+
+	t.Run("Skipped when already ordering by the column", func(t *testing.T) {
+		params := dto.Params{Order: "id", Dir: "desc"}
+		opts := NewOptions().WithStableSort("id")
+
+		sql := dryRunSQL(applyOrdering(db.Model(&TestUser{}), params, orderable, opts))
+		if !strings.HasSuffix(sql, "ORDER BY id desc") {
+			t.Errorf("Expected no duplicate tiebreaker, got %s", sql)
+		}
+	})
+
+	t.Run("Skipped when present in default order", func(t *testing.T) {
+		opts := NewOptions().WithDefaultOrder("status DESC, id DESC").WithStableSort("id")
+
+		sql := dryRunSQL(applyOrdering(db.Model(&TestUser{}), dto.Params{}, orderable, opts))
+		if !strings.HasSuffix(sql, "ORDER BY status DESC, id DESC") {
+			t.Errorf("Expected no duplicate tiebreaker, got %s", sql)
+		}
+	})
+}
+
+func TestOfReturnInvalidStableSortColumn(t *testing.T) {
+	db := newFakeGormDB(t, &fakeDB{})
+	c, _ := newTestContext("")
+
+	var users []TestUser
+	_, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithStableSort("id; DROP TABLE users"))
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+	}
+}
+
+func TestOfReturnQueryTimeout(t *testing.T) {
+	t.Run("Slow query returns inline error", func(t *testing.T) {
+		fake := &fakeDB{
+			handler: func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+				<-ctx.Done()
+				return fakeResult{}, ctx.Err()
+			},
+		}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("draw=5")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithQueryTimeout(20*time.Millisecond))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Error != ErrQueryTimeout.Error() {
+			t.Errorf("Expected inline error %q, got %q", ErrQueryTimeout.Error(), result.Error)
+		}
+		if result.Draw != 5 {
+			t.Errorf("Expected draw=5, got %d", result.Draw)
+		}
+		if rows, ok := result.Data.([]map[string]interface{}); !ok || rows == nil {
+			t.Errorf("Expected empty data slice, got %#v", result.Data)
+		}
+	})
+
+	t.Run("Fast query is unaffected", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithQueryTimeout(time.Second))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Error != "" || result.RecordsTotal != 1 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("Caller's deadline on the query is kept", func(t *testing.T) {
+		fake := &fakeDB{
+			handler: func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+				<-ctx.Done()
+				return fakeResult{}, ctx.Err()
+			},
+		}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		started := time.Now()
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}).WithContext(ctx), &users, nil, nil, NewOptions().WithQueryTimeout(time.Minute))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Error != ErrQueryTimeout.Error() || time.Since(started) > 10*time.Second {
+			t.Errorf("Expected the caller's deadline to end the query, got %+v after %v", result, time.Since(started))
+		}
+	})
+
+	t.Run("Caller's cancellation on the query is kept", func(t *testing.T) {
+		fake := &fakeDB{
+			handler: func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+				<-ctx.Done()
+				return fakeResult{}, ctx.Err()
+			},
+		}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var users []TestUser
+		_, err := OfReturn(c, db.Model(&TestUser{}).WithContext(ctx), &users, nil, nil, NewOptions().WithQueryTimeout(time.Minute))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Request cancellation cancels the queries", func(t *testing.T) {
+		fake := &fakeDB{
+			handler: func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+				<-ctx.Done()
+				return fakeResult{}, ctx.Err()
+			},
+		}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		var users []TestUser
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithQueryTimeout(time.Minute))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Errors are returned without a timeout", func(t *testing.T) {
+		fake := &fakeDB{
+			handler: func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+				return fakeResult{}, context.DeadlineExceeded
+			},
+		}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions()); err == nil {
+			t.Error("Expected error to be returned")
+		}
+	})
+}
+
+func TestApplyOrderingDisabled(t *testing.T) {
+	db := newDryRunDB(t)
+	orderable := map[string]string{"name": "name"}
+	params := dto.Params{Order: "name", Dir: "desc"}
+
+	t.Run("Client order is ignored", func(t *testing.T) {
+		sql := dryRunSQL(applyOrdering(db.Model(&TestUser{}), params, orderable, NewOptions().WithOrderingDisabled(true)))
+		if strings.Contains(sql, "ORDER BY") {
+			t.Errorf("Expected natural order, got %s", sql)
+		}
+	})
+
+	t.Run("Default order still applies", func(t *testing.T) {
+		opts := NewOptions().WithOrderingDisabled(true).WithDefaultOrder("id DESC")
+
+		sql := dryRunSQL(applyOrdering(db.Model(&TestUser{}), params, orderable, opts))
+		if !strings.HasSuffix(sql, "ORDER BY id DESC") {
+			t.Errorf("Expected default order only, got %s", sql)
+		}
+	})
+}
+
+func TestOfReturnAbsoluteTotal(t *testing.T) {
+	fake := &fakeDB{}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("")
+
+	var users []TestUser
+	base := db.Model(&TestUser{}).Where("email LIKE ?", "%@example.com")
+	if _, err := OfReturn(c, base, &users, nil, nil, NewOptions().WithAbsoluteTotal(&TestUser{})); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	queries := fake.Queries()
+	if queries[0].SQL != "SELECT count(*) FROM `test_users`" {
+		t.Errorf("Expected total to ignore base conditions, got %s", queries[0].SQL)
+	}
+	if !strings.Contains(queries[1].SQL, "WHERE email LIKE ?") {
+		t.Errorf("Expected filtered count to keep base conditions, got %s", queries[1].SQL)
+	}
+}
+
+func TestApplyOrderingValidatesDir(t *testing.T) {
+	db := newDryRunDB(t)
+	orderable := map[string]string{"name": "name"}
+
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{"desc; DROP TABLE users--", "ORDER BY name asc"},
+		{"DESC", "ORDER BY name desc"},
+		{"", "ORDER BY name asc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			params := dto.Params{Order: "name", Dir: tt.dir}
+
+			sql := dryRunSQL(applyOrdering(db.Model(&TestUser{}), params, orderable, NewOptions()))
+			if !strings.HasSuffix(sql, tt.expected) {
+				t.Errorf("Expected %q, got %s", tt.expected, sql)
+			}
+		})
+	}
+}
+
+func TestOfReturnMaxPageSizeFunc(t *testing.T) {
+	opts := NewOptions().WithMaxPageSizeFunc(func(c *gin.Context) int {
+		if c.GetString("role") == "admin" {
+			return 5000
+		}
+		return 100
+	})
+
+	tests := []struct {
+		role     string
+		expected string
+	}{
+		{"admin", "LIMIT 5000"},
+		{"user", "LIMIT 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			fake := &fakeDB{}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("length=10000")
+			c.Set("role", tt.role)
+
+			var users []TestUser
+			if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			q := fake.LastSelect()
+			limit := q.Args[len(q.Args)-1]
+			if !strings.Contains(q.SQL, "LIMIT ?") || "LIMIT "+fmt.Sprint(limit) != tt.expected {
+				t.Errorf("Expected %s, got %s %v", tt.expected, q.SQL, q.Args)
+			}
+		})
+	}
+}
+
+func TestOfReturnSkipsRedundantFilteredCount(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		searchable []string
+		opts       Options
+		counts     int
+	}{
+		{"No search", "", []string{"name"}, NewOptions(), 1},
+		{"Search without searchable columns", "search[value]=john", nil, NewOptions(), 1},
+		{"Search applied", "search[value]=john", []string{"name"}, NewOptions(), 2},
+		{"Absolute total", "", []string{"name"}, NewOptions().WithAbsoluteTotal(&TestUser{}), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 4}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext(tt.query)
+
+			var users []TestUser
+			result, err := OfReturn(c, db.Model(&TestUser{}), &users, tt.searchable, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := fake.CountQueries(); got != tt.counts {
+				t.Errorf("Expected %d count queries, got %d", tt.counts, got)
+			}
+			if result.RecordsFiltered != 4 {
+				t.Errorf("Expected recordsFiltered=4, got %d", result.RecordsFiltered)
+			}
+		})
+	}
+}
+
+func TestOfParams(t *testing.T) {
+	fake := &fakeDB{count: 30, result: userResult(TestUser{ID: 26, Name: "John"})}
+	db := newFakeGormDB(t, fake)
+
+	params := dto.NewParams().WithSearch("jo").WithPage(2, 25).WithOrder("name", "DESC").Build()
+
+	var users []TestUser
+	result, err := OfParams(db.Model(&TestUser{}), &users, params, []string{"name"}, map[string]string{"name": "name"}, NewOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := fake.LastSelect()
+	if !strings.Contains(q.SQL, "ORDER BY name desc LIMIT ? OFFSET ?") {
+		t.Errorf("Unexpected query: %s", q.SQL)
+	}
+	if q.Args[len(q.Args)-1] != int64(25) {
+		t.Errorf("Expected offset 25, got %v", q.Args)
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if rows[0]["DT_RowIndex"] != 26 {
+		t.Errorf("Expected DT_RowIndex=26, got %v", rows[0]["DT_RowIndex"])
+	}
+}
+
+func TestOfReturnSearchRemovedColumn(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"Remove", NewOptions().Remove("email")},
+		{"WithSearchableHidden", NewOptions().WithSearchableHidden("email")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("search[value]=example")
+
+			searchable := []string{"name"}
+			if len(tt.opts.SearchableHidden) == 0 {
+				searchable = append(searchable, "email")
+			}
+
+			var users []TestUser
+			result, err := OfReturn(c, db.Model(&TestUser{}), &users, searchable, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			q := fake.LastSelect()
+			if !strings.Contains(q.SQL, "LOWER(email) LIKE LOWER(?)") {
+				t.Errorf("Expected search on email, got %s", q.SQL)
+			}
+
+			rows := result.Data.([]map[string]interface{})
+			if len(rows) != 1 {
+				t.Fatalf("Expected 1 row, got %d", len(rows))
+			}
+			if _, ok := rows[0]["email"]; ok {
+				t.Errorf("Expected email to be removed, got %v", rows[0])
+			}
+		})
+	}
+}
+
+func TestOfReturnInitialSearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawQuery string
+		expected string
+	}{
+		{"Empty client search uses the initial search", "search[value]=", "%open%"},
+		{"Client search overrides the initial search", "search[value]=closed", "%closed%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 1}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext(tt.rawQuery)
+
+			var users []TestUser
+			opts := NewOptions().WithInitialSearch("open")
+			if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			q := fake.LastSelect()
+			if !strings.Contains(q.SQL, "LOWER(name) LIKE LOWER(?)") {
+				t.Fatalf("Expected search condition, got %s", q.SQL)
+			}
+			if len(q.Args) == 0 || q.Args[0] != tt.expected {
+				t.Errorf("Expected search arg %q, got %v", tt.expected, q.Args)
+			}
+		})
+	}
+}
+
+func TestOfReturnEmptyDataIsArray(t *testing.T) {
+	for _, opts := range []Options{NewOptions(), NewOptions().WithInMemorySearch(true)} {
+		fake := &fakeDB{count: 0}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=nobody")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Failed to marshal result: %v", err)
+		}
+		if !strings.Contains(string(body), `"data":[]`) {
+			t.Errorf("Expected data to be an empty array, got %s", body)
+		}
+	}
+}
+
+func TestOfReturnFetchHook(t *testing.T) {
+	t.Run("Hook mutates the typed slice", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "john"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		opts := NewOptions().WithFetchHook(func(dest interface{}) error {
+			users := *dest.(*[]TestUser)
+			for i := range users {
+				users[i].Name = strings.ToUpper(users[i].Name)
+			}
+			return nil
+		})
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		rows := result.Data.([]map[string]interface{})
+		if rows[0]["name"] != "JOHN" {
+			t.Errorf("Expected name='JOHN', got %v", rows[0]["name"])
+		}
+	})
+
+	t.Run("Hook error aborts", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "john"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		hookErr := fmt.Errorf("decorate failed")
+		var users []TestUser
+		opts := NewOptions().WithFetchHook(func(dest interface{}) error { return hookErr })
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts); err != hookErr {
+			t.Errorf("Expected hook error, got %v", err)
+		}
+	})
+}
+
+func TestOfReturnFieldSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawQuery string
+		opts     Options
+		expected []string
+	}{
+		{"Subset with unknown field", "fields=name,+email,nope", NewOptions().WithFieldSelection(""), []string{"DT_RowIndex", "email", "name"}},
+		{"No fields param", "", NewOptions().WithFieldSelection(""), []string{"DT_RowIndex", "email", "id", "name"}},
+		{"Only unknown fields", "fields=nope", NewOptions().WithFieldSelection(""), []string{"DT_RowIndex", "email", "id", "name"}},
+		{"Disabled by default", "fields=name", NewOptions(), []string{"DT_RowIndex", "email", "id", "name"}},
+		{"Custom param and output keys", "only=userName", NewOptions().WithFieldSelection("only").WithColumnAlias("name", "user_name").WithKeyCase(CamelCase), []string{"DT_RowIndex", "userName"}},
+		{"Row id and custom index kept", "fields=id", NewOptions().WithFieldSelection("").WithIndex("no", false).WithRowId("id"), []string{"DT_RowId", "id", "no"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext(tt.rawQuery)
+
+			var users []TestUser
+			result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			row := result.Data.([]map[string]interface{})[0]
+			keys := make([]string, 0, len(row))
+			for key := range row {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			if strings.Join(keys, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected keys %v, got %v", tt.expected, keys)
+			}
+		})
+	}
+}
+
+func TestOfReturnLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawQuery string
+		rows     int
+		fetch    string // expected fetch query, empty for no fetch
+	}{
+		{"Zero length returns counts only", "length=0", 0, ""},
+		{"Minus one returns all rows", "length=-1", 2, "SELECT * FROM `test_users`"},
+		{"Positive length paginates", "start=10&length=5", 2, "SELECT * FROM `test_users` LIMIT ? OFFSET ?"},
+		{"Invalid length uses the default", "length=abc", 2, "SELECT * FROM `test_users` LIMIT ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{count: 12, result: userResult(TestUser{ID: 1, Name: "John"}, TestUser{ID: 2, Name: "Jane"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext(tt.rawQuery)
+
+			var users []TestUser
+			result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if rows := result.Data.([]map[string]interface{}); len(rows) != tt.rows {
+				t.Errorf("Expected %d rows, got %d", tt.rows, len(rows))
+			}
+			if result.RecordsTotal != 12 || result.RecordsFiltered != 12 {
+				t.Errorf("Expected counts 12/12, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+			}
+			if last := fake.LastSelect(); last.SQL != tt.fetch {
+				t.Errorf("Expected fetch %q, got %q", tt.fetch, last.SQL)
+			}
+		})
+	}
+
+	t.Run("Zero length with in-memory search", func(t *testing.T) {
+		fake := &fakeDB{count: 2, result: userResult(TestUser{ID: 1, Name: "John"}, TestUser{ID: 2, Name: "Jane"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("length=0&search[value]=john")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithInMemorySearch(true))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rows := result.Data.([]map[string]interface{}); len(rows) != 0 || result.RecordsFiltered != 1 {
+			t.Errorf("Expected no rows and 1 filtered record, got %v and %d", rows, result.RecordsFiltered)
+		}
+	})
+}
+
+func TestOfReturnPagePostProcess(t *testing.T) {
+	t.Run("Hook receives only the current page", func(t *testing.T) {
+		fake := &fakeDB{count: 30, result: userResult(TestUser{ID: 11, Name: "John"}, TestUser{ID: 12, Name: "Jane"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("start=10&length=2")
+
+		var seen int
+		opts := NewOptions().
+			Add("action", func(row map[string]interface{}) interface{} { return "edit" }).
+			WithPagePostProcess(func(rows []map[string]interface{}) ([]map[string]interface{}, error) {
+				seen = len(rows)
+				for _, row := range rows {
+					row["avatar"] = fmt.Sprintf("/avatars/%v.png", row["id"])
+				}
+				return rows[:1], nil
+			})
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		rows := result.Data.([]map[string]interface{})
+		if seen != 2 || len(rows) != 1 {
+			t.Fatalf("Expected the hook to see 2 rows and return 1, got %d and %d", seen, len(rows))
+		}
+		if rows[0]["avatar"] != "/avatars/11.png" || rows[0]["action"] != "edit" || rows[0]["DT_RowIndex"] != 11 {
+			t.Errorf("Expected a decorated, transformed row, got %v", rows[0])
+		}
+	})
+
+	t.Run("Hook error aborts", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		hookErr := fmt.Errorf("avatar service down")
+		opts := NewOptions().WithPagePostProcess(func(rows []map[string]interface{}) ([]map[string]interface{}, error) {
+			return nil, hookErr
+		})
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts); err != hookErr {
+			t.Errorf("Expected hook error, got %v", err)
+		}
+	})
+}
+
+func TestOfReturnEmptyMessage(t *testing.T) {
+	opts := NewOptions().WithEmptyMessage("No matching records")
+
+	t.Run("Message is set when nothing matches", func(t *testing.T) {
+		fake := &fakeDB{count: 0}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=nobody")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.Message != "No matching records" || result.Error != "" {
+			t.Errorf("Expected the empty message without an error, got %+v", result)
+		}
+
+		body, _ := json.Marshal(result)
+		if !strings.Contains(string(body), `"message":"No matching records"`) {
+			t.Errorf("Expected message in the response, got %s", body)
+		}
+	})
+
+	t.Run("Message is omitted when records match", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		body, _ := json.Marshal(result)
+		if result.Message != "" || strings.Contains(string(body), `"message"`) {
+			t.Errorf("Expected no message, got %s", body)
+		}
+	})
+}
+
+func TestOfReturnMaxOffset(t *testing.T) {
+	t.Run("Over-cap start is clamped", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("start=10000000&length=10")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithMaxOffset(1000, false))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		last := fake.LastSelect()
+		if !strings.Contains(last.SQL, "OFFSET ?") || last.Args[len(last.Args)-1] != int64(1000) {
+			t.Errorf("Expected offset clamped to 1000, got %s %v", last.SQL, last.Args)
+		}
+		if rows := result.Data.([]map[string]interface{}); rows[0]["DT_RowIndex"] != 1001 {
+			t.Errorf("Expected the index to follow the clamped offset, got %v", rows[0]["DT_RowIndex"])
+		}
+	})
+
+	t.Run("Over-cap start is rejected", func(t *testing.T) {
+		fake := &fakeDB{count: 1}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("start=10000000&length=10")
+
+		var users []TestUser
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithMaxOffset(1000, true))
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "start" {
+			t.Fatalf("Expected a start ValidationError, got %v", err)
+		}
+		if len(fake.Queries()) != 0 {
+			t.Errorf("Expected no queries for a rejected offset, got %v", fake.Queries())
+		}
+	})
+
+	t.Run("Offsets within the cap are unchanged", func(t *testing.T) {
+		for _, start := range []int{0, 500, 1000} {
+			if got, err := limitOffset(start, NewOptions().WithMaxOffset(1000, true)); err != nil || got != start {
+				t.Errorf("Expected start %d to pass, got %d (err %v)", start, got, err)
+			}
+		}
+		if got, err := limitOffset(10000000, NewOptions()); err != nil || got != 10000000 {
+			t.Errorf("Expected unlimited offsets by default, got %d (err %v)", got, err)
+		}
+	})
+}
+
+func TestOfReturnRowFilter(t *testing.T) {
+	fake := &fakeDB{count: 10, result: userResult(
+		TestUser{ID: 1, Name: "John", Email: "john@example.com"},
+		TestUser{ID: 2, Name: "Jane", Email: "jane@internal"},
+		TestUser{ID: 3, Name: "Bob", Email: "bob@example.com"},
+	)}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("start=0&length=3")
+
+	opts := NewOptions().
+		Remove("email").
+		WithRowFilter(func(row map[string]interface{}) bool {
+			return !strings.HasSuffix(row["email"].(string), "@internal")
+		})
+
+	var users []TestUser
+	result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if len(rows) != 2 || rows[0]["name"] != "John" || rows[1]["name"] != "Bob" {
+		t.Fatalf("Expected John and Bob, got %v", rows)
+	}
+	if rows[1]["DT_RowIndex"] != 2 {
+		t.Errorf("Expected contiguous indexes, got %v", rows[1]["DT_RowIndex"])
+	}
+	if result.RecordsDropped != 1 || result.RecordsTotal != 10 || result.RecordsFiltered != 10 {
+		t.Errorf("Expected 1 dropped row with unchanged counts, got %+v", result)
+	}
+
+	body, _ := json.Marshal(result)
+	if !strings.Contains(string(body), `"recordsDropped":1`) {
+		t.Errorf("Expected recordsDropped in the response, got %s", body)
+	}
+
+	t.Run("Omitted without dropped rows", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if body, _ := json.Marshal(result); strings.Contains(string(body), "recordsDropped") {
+			t.Errorf("Expected no recordsDropped field, got %s", body)
+		}
+	})
+}
+
+func TestOfReturnCursor(t *testing.T) {
+	opts := NewOptions().WithCursor("id")
+
+	t.Run("First page returns the next cursor", func(t *testing.T) {
+		fake := &fakeDB{count: 5, result: userResult(TestUser{ID: 1, Name: "John"}, TestUser{ID: 2, Name: "Jane"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("start=40&length=2&order[0][column]=name&order[0][dir]=desc")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, map[string]string{"name": "name"}, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "SELECT * FROM `test_users` ORDER BY id ASC LIMIT ?"
+		if last := fake.LastSelect(); last.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+		}
+		if result.NextCursor != 2 || result.RecordsTotal != 5 {
+			t.Errorf("Expected next cursor 2 with total 5, got %+v", result)
+		}
+	})
+
+	t.Run("Cursor fetches the rows after it", func(t *testing.T) {
+		fake := &fakeDB{count: 5, result: userResult(TestUser{ID: 3, Name: "Bob"}, TestUser{ID: 4, Name: "Alice"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("length=2&cursor=2&search[value]=a")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		last := fake.LastSelect()
+		expected := "SELECT * FROM `test_users` WHERE LOWER(name) LIKE LOWER(?) AND id > ? ORDER BY id ASC LIMIT ?"
+		if last.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+		}
+		if len(last.Args) != 3 || last.Args[1] != int64(2) {
+			t.Errorf("Expected a numeric cursor argument, got %v", last.Args)
+		}
+		if result.NextCursor != 4 {
+			t.Errorf("Expected next cursor 4, got %v", result.NextCursor)
+		}
+		for _, q := range fake.Queries() {
+			if isCountQuery(q.SQL) && strings.Contains(q.SQL, "id >") {
+				t.Errorf("Expected counts without the cursor condition, got %s", q.SQL)
+			}
+		}
+	})
+
+	t.Run("Multi-column search is grouped before the cursor condition", func(t *testing.T) {
+		fake := &fakeDB{count: 5, result: userResult(TestUser{ID: 3, Name: "Bob"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("length=2&cursor=2&search[value]=a")
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name", "email"}, nil, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "SELECT * FROM `test_users` WHERE (LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)) AND id > ? ORDER BY id ASC LIMIT ?"
+		if last := fake.LastSelect(); last.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+		}
+	})
+
+	t.Run("Last page has no next cursor", func(t *testing.T) {
+		fake := &fakeDB{count: 5, result: userResult(TestUser{ID: 5, Name: "Eve"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("length=2&cursor=4")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if body, _ := json.Marshal(result); result.NextCursor != nil || strings.Contains(string(body), "nextCursor") {
+			t.Errorf("Expected no next cursor, got %s", body)
+		}
+	})
+
+	t.Run("Invalid cursor column", func(t *testing.T) {
+		db := newFakeGormDB(t, &fakeDB{})
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithCursor("id; DROP TABLE users"))
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected a ValidationError, got %v", err)
+		}
+	})
+}
+
+type cursorUntaggedUser struct {
+	ID   int
+	Name string
+}
+
+type cursorRenamedUser struct {
+	ID   int    `json:"user_id"`
+	Name string `json:"name"`
+}
+
+type cursorEmbeddedUser struct {
+	gorm.Model
+	Name string `json:"name"`
+}
+
+type cursorHiddenUser struct {
+	ID   int    `json:"-"`
+	Name string `json:"name"`
+}
+
+// cursorAfter fetches a full first keyset page of two rows into T and
+// returns the next cursor.
+func cursorAfter[T any](t *testing.T) (interface{}, error) {
+	t.Helper()
+
+	fake := &fakeDB{count: 5, result: userResult(TestUser{ID: 1, Name: "John"}, TestUser{ID: 2, Name: "Jane"})}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("length=2")
+
+	var dest []T
+	result, err := OfReturn(c, db.Table("test_users"), &dest, nil, nil, NewOptions().WithCursor("id"))
+	return result.NextCursor, err
+}
+
+func TestOfReturnCursorRowKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		cursor   func(t *testing.T) (interface{}, error)
+		expected interface{}
+	}{
+		{"Untagged field", cursorAfter[cursorUntaggedUser], 2},
+		{"JSON tag differs from the column", cursorAfter[cursorRenamedUser], 2},
+		{"Embedded gorm.Model", cursorAfter[cursorEmbeddedUser], uint(2)},
+		{"Map rows", cursorAfter[map[string]interface{}], int64(2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := tt.cursor(t)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cursor != tt.expected {
+				t.Errorf("Expected next cursor %#v, got %#v", tt.expected, cursor)
+			}
+		})
+	}
+
+	t.Run("Cursor column missing from the rows", func(t *testing.T) {
+		_, err := cursorAfter[cursorHiddenUser](t)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "id" {
+			t.Errorf("Expected a ValidationError on id, got %v", err)
+		}
+	})
+}
+
+func TestOfReturnTimings(t *testing.T) {
+	run := func(opts Options) (dto.Datatables, string) {
+		fake := &fakeDB{count: 2, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		body, _ := json.Marshal(result)
+		return result, string(body)
+	}
+
+	t.Run("Timings are attached when enabled", func(t *testing.T) {
+		result, body := run(NewOptions().WithTimings(true))
+
+		if result.Timings == nil {
+			t.Fatalf("Expected timings, got %s", body)
+		}
+		if result.Timings.TotalCount <= 0 || result.Timings.FilteredCount <= 0 || result.Timings.Fetch <= 0 {
+			t.Errorf("Expected positive durations, got %+v", result.Timings)
+		}
+		for _, field := range []string{`"DT_Timings":{`, `"totalCount":`, `"filteredCount":`, `"fetch":`} {
+			if !strings.Contains(body, field) {
+				t.Errorf("Expected %s in the response, got %s", field, body)
+			}
+		}
+	})
+
+	t.Run("Timings are omitted by default", func(t *testing.T) {
+		if result, body := run(NewOptions()); result.Timings != nil || strings.Contains(body, "DT_Timings") {
+			t.Errorf("Expected no timings, got %s", body)
+		}
+	})
+}
+
+func TestOfReturnResultLimit(t *testing.T) {
+	run := func(rawQuery string, count int64, opts Options) (dto.Datatables, *fakeDB) {
+		fake := &fakeDB{count: count, result: userResult(TestUser{ID: 1, Name: "John"}, TestUser{ID: 2, Name: "Jane"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext(rawQuery)
+
+		var users []TestUser
+		result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result, fake
+	}
+
+	t.Run("length=-1 is capped", func(t *testing.T) {
+		result, fake := run("length=-1", 1000000, NewOptions().WithResultLimit(2))
+
+		last := fake.LastSelect()
+		if !strings.HasSuffix(last.SQL, "LIMIT ?") || last.Args[len(last.Args)-1] != int64(2) {
+			t.Errorf("Expected LIMIT 2, got %s %v", last.SQL, last.Args)
+		}
+		if !result.Truncated || result.RecordsFiltered != 1000000 {
+			t.Errorf("Expected a truncated result with the full count, got %+v", result)
+		}
+
+		body, _ := json.Marshal(result)
+		if !strings.Contains(string(body), `"truncated":true`) {
+			t.Errorf("Expected truncated in the response, got %s", body)
+		}
+	})
+
+	t.Run("Sets within the limit are not truncated", func(t *testing.T) {
+		result, _ := run("length=-1", 2, NewOptions().WithResultLimit(2))
+
+		if result.Truncated {
+			t.Errorf("Expected a complete result, got %+v", result)
+		}
+	})
+
+	t.Run("Smaller pages are unchanged", func(t *testing.T) {
+		result, fake := run("length=1", 100, NewOptions().WithResultLimit(2))
+
+		if last := fake.LastSelect(); last.Args[len(last.Args)-1] != int64(1) || result.Truncated {
+			t.Errorf("Expected LIMIT 1 without truncation, got %v %+v", last.Args, result)
+		}
+	})
+
+	t.Run("Unlimited by default", func(t *testing.T) {
+		_, fake := run("length=-1", 100, NewOptions())
+
+		if last := fake.LastSelect(); strings.Contains(last.SQL, "LIMIT") {
+			t.Errorf("Expected no LIMIT, got %s", last.SQL)
+		}
+	})
+}
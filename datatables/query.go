@@ -0,0 +1,84 @@
+package datatables
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ComputedColumn is a column computed by the database from a raw SQL expression.
+type ComputedColumn struct {
+	Alias string // Output column name, validated with isValidColumnName
+	Expr  string // Developer-trusted SQL expression (e.g. a correlated subquery)
+}
+
+// onHandle returns a copy of the query that runs on the connection pool of
+// handle (e.g. a read replica), keeping the query's conditions and context.
+// The original query is not modified.
+func onHandle(query, handle *gorm.DB) *gorm.DB {
+	tx := query.Session(&gorm.Session{}).Clauses() // clones the statement
+	tx.Statement.ConnPool = handle.Statement.ConnPool
+	return tx
+}
+
+// HavingClause is a HAVING condition for grouped queries (see Options.WithHaving).
+type HavingClause struct {
+	Condition string        // Developer-trusted SQL condition (e.g. "COUNT(*) > ?")
+	Args      []interface{} // Parameterized arguments of the condition
+}
+
+// applyHaving adds the HAVING conditions to the query.
+//
+// Returns the query and whether any condition was applied.
+func applyHaving(query *gorm.DB, clauses []HavingClause) (*gorm.DB, bool) {
+	for _, clause := range clauses {
+		query = query.Having(clause.Condition, clause.Args...)
+	}
+	return query, len(clauses) > 0
+}
+
+// applyComputedColumns adds the computed columns to the SELECT list of the query.
+//
+// When the query has no explicit Select, the model's columns are selected
+// with "<table>.*" so the computed columns are fetched alongside them:
+//   SELECT users.*, (SELECT COUNT(*) FROM orders WHERE orders.user_id = users.id) AS order_count
+//
+// Returns an error if the model's schema cannot be parsed.
+func applyComputedColumns(query *gorm.DB, model interface{}, computed []ComputedColumn) (*gorm.DB, error) {
+	if len(computed) == 0 {
+		return query, nil
+	}
+
+	selects := append([]string(nil), query.Statement.Selects...)
+	if len(selects) == 0 {
+		table := query.Statement.Table
+		if table == "" {
+			stmt := &gorm.Statement{DB: query}
+			if err := stmt.Parse(model); err != nil {
+				return nil, err
+			}
+			table = stmt.Schema.Table
+		}
+		selects = append(selects, table+".*")
+	}
+
+	for _, col := range computed {
+		selects = append(selects, "("+col.Expr+") AS "+col.Alias)
+	}
+
+	return query.Select(strings.Join(selects, ", ")), nil
+}
+
+// countRecords counts the rows the query returns into count.
+//
+// With grouped set, the query is wrapped in a subquery so GROUP BY and
+// DISTINCT queries count the resulting rows rather than the underlying ones:
+//   SELECT count(*) FROM (SELECT category, SUM(price) AS total FROM products GROUP BY category) AS dt_count
+func countRecords(query *gorm.DB, grouped bool, count *int64) error {
+	if grouped {
+		return query.Session(&gorm.Session{NewDB: true}).
+			Table("(?) AS dt_count", query.Session(&gorm.Session{})).
+			Count(count).Error
+	}
+	return query.Session(&gorm.Session{}).Count(count).Error
+}
@@ -0,0 +1,327 @@
+package datatables
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type TestUserWithOrders struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	OrderCount int    `json:"order_count" gorm:"column:order_count;->"`
+}
+
+func (TestUserWithOrders) TableName() string { return "users" }
+
+func TestOfReturnComputedColumn(t *testing.T) {
+	fake := &fakeDB{
+		count: 2,
+		result: fakeResult{
+			columns: []string{"id", "name", "order_count"},
+			rows: [][]driver.Value{
+				{int64(2), "Jane", int64(5)},
+				{int64(1), "John", int64(1)},
+			},
+		},
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("order[0][column]=orders&order[0][dir]=desc")
+
+	var users []TestUserWithOrders
+	opts := NewOptions().WithComputed("order_count", "SELECT COUNT(*) FROM orders WHERE orders.user_id = users.id")
+	result, err := OfReturn(c, db.Model(&TestUserWithOrders{}), &users, []string{"name"}, map[string]string{"orders": "order_count"}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sql := fake.LastSelect().SQL
+	expected := "SELECT users.*, (SELECT COUNT(*) FROM orders WHERE orders.user_id = users.id) AS order_count FROM `users` ORDER BY order_count desc"
+	if !strings.HasPrefix(sql, expected) {
+		t.Errorf("Expected %q, got %q", expected, sql)
+	}
+
+	for _, q := range fake.Queries() {
+		if isCountQuery(q.SQL) && strings.Contains(q.SQL, "order_count") {
+			t.Errorf("Computed columns should not be part of count queries: %s", q.SQL)
+		}
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if rows[0]["order_count"] != 5 {
+		t.Errorf("Expected order_count=5, got %v", rows[0]["order_count"])
+	}
+}
+
+func TestValidateComputedColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		column    ComputedColumn
+		shouldErr bool
+	}{
+		{"Valid", ComputedColumn{Alias: "order_count", Expr: "SELECT 1"}, false},
+		{"Invalid alias", ComputedColumn{Alias: "x; DROP TABLE users", Expr: "SELECT 1"}, true},
+		{"Empty expression", ComputedColumn{Alias: "total", Expr: " "}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateComputedColumns([]ComputedColumn{tt.column})
+			if (err != nil) != tt.shouldErr {
+				t.Errorf("validateComputedColumns() error = %v, shouldErr %v", err, tt.shouldErr)
+			}
+		})
+	}
+}
+
+func TestOfReturnGroupedCount(t *testing.T) {
+	fake := &fakeDB{
+		count: 3,
+		result: fakeResult{
+			columns: []string{"category", "total"},
+			rows:    [][]driver.Value{{"books", int64(2)}},
+		},
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("search[value]=bo")
+
+	type categoryTotal struct {
+		Category string `json:"category"`
+		Total    int    `json:"total"`
+	}
+
+	var totals []categoryTotal
+	query := db.Table("products").Select("category, COUNT(*) AS total").Group("category")
+	opts := NewOptions().WithGroupedCount(true)
+	result, err := OfReturn(c, query, &totals, []string{"category"}, nil, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 3 {
+		t.Fatalf("Expected 3 queries, got %d", len(queries))
+	}
+
+	expectedTotal := "SELECT count(*) FROM (SELECT category, COUNT(*) AS total FROM `products` GROUP BY `category`) AS dt_count"
+	if queries[0].SQL != expectedTotal {
+		t.Errorf("Unexpected total count query:\n got  %s\n want %s", queries[0].SQL, expectedTotal)
+	}
+
+	expectedFiltered := "SELECT count(*) FROM (SELECT category, COUNT(*) AS total FROM `products` WHERE LOWER(category) LIKE LOWER(?) GROUP BY `category`) AS dt_count"
+	if queries[1].SQL != expectedFiltered {
+		t.Errorf("Unexpected filtered count query:\n got  %s\n want %s", queries[1].SQL, expectedFiltered)
+	}
+
+	if !strings.HasPrefix(queries[2].SQL, "SELECT category, COUNT(*) AS total FROM `products`") {
+		t.Errorf("Unexpected fetch query: %s", queries[2].SQL)
+	}
+	if result.RecordsTotal != 3 || result.RecordsFiltered != 3 {
+		t.Errorf("Expected total=3 filtered=3, got %d/%d", result.RecordsTotal, result.RecordsFiltered)
+	}
+}
+
+func TestOfReturnHaving(t *testing.T) {
+	fake := &fakeDB{
+		count: 2,
+		result: fakeResult{
+			columns: []string{"category", "total"},
+			rows:    [][]driver.Value{{"books", int64(7)}},
+		},
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("search[value]=bo")
+
+	type categoryTotal struct {
+		Category string `json:"category"`
+		Total    int    `json:"total"`
+	}
+
+	var totals []categoryTotal
+	query := db.Table("products").Select("category, COUNT(*) AS total").Group("category")
+	opts := NewOptions().WithGroupedCount(true).WithHaving("COUNT(*) > ?", 5).WithHaving("SUM(price) < ?", 1000)
+	if _, err := OfReturn(c, query, &totals, []string{"category"}, nil, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 3 {
+		t.Fatalf("Expected 3 queries, got %d", len(queries))
+	}
+
+	if strings.Contains(queries[0].SQL, "HAVING") {
+		t.Errorf("Expected the total count without HAVING, got %s", queries[0].SQL)
+	}
+
+	expectedFiltered := "SELECT count(*) FROM (SELECT category, COUNT(*) AS total FROM `products` WHERE LOWER(category) LIKE LOWER(?) GROUP BY `category` HAVING COUNT(*) > ? AND SUM(price) < ?) AS dt_count"
+	if queries[1].SQL != expectedFiltered {
+		t.Errorf("Unexpected filtered count query:\n got  %s\n want %s", queries[1].SQL, expectedFiltered)
+	}
+
+	expectedFetch := "SELECT category, COUNT(*) AS total FROM `products` WHERE LOWER(category) LIKE LOWER(?) GROUP BY `category` HAVING COUNT(*) > ? AND SUM(price) < ? LIMIT ?"
+	if queries[2].SQL != expectedFetch {
+		t.Errorf("Unexpected fetch query:\n got  %s\n want %s", queries[2].SQL, expectedFetch)
+	}
+	if len(queries[2].Args) != 4 || queries[2].Args[1] != int64(5) || queries[2].Args[2] != int64(1000) {
+		t.Errorf("Expected parameterized HAVING args, got %v", queries[2].Args)
+	}
+}
+
+func TestOfReturnSeparateHandles(t *testing.T) {
+	base := &fakeDB{count: 3, result: userResult(TestUser{ID: 9, Name: "Base"})}
+	replica := &fakeDB{count: 100, result: userResult(TestUser{ID: 8, Name: "Replica"})}
+	primary := &fakeDB{count: 50, result: userResult(TestUser{ID: 1, Name: "John"})}
+
+	db := newFakeGormDB(t, base)
+	c, _ := newTestContext("search[value]=jo")
+
+	var users []TestUser
+	opts := NewOptions().WithTotalDB(newFakeGormDB(t, replica)).WithFetchDB(newFakeGormDB(t, primary))
+	result, err := OfReturn(c, db.Model(&TestUser{}).Where("active = ?", true), &users, []string{"name"}, nil, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(replica.Queries()) != 1 || replica.CountQueries() != 1 {
+		t.Fatalf("Expected only the total count on the replica, got %v", replica.Queries())
+	}
+	if sql := replica.Queries()[0].SQL; sql != "SELECT count(*) FROM `test_users` WHERE active = ?" {
+		t.Errorf("Expected the total count to keep the query's conditions, got %s", sql)
+	}
+
+	if len(primary.Queries()) != 1 || primary.CountQueries() != 0 {
+		t.Fatalf("Expected only the fetch on the fetch handle, got %v", primary.Queries())
+	}
+	if sql := primary.LastSelect().SQL; !strings.Contains(sql, "WHERE active = ? AND LOWER(name) LIKE LOWER(?)") {
+		t.Errorf("Expected the fetch to keep the filters, got %s", sql)
+	}
+
+	if len(base.Queries()) != 1 || base.CountQueries() != 1 {
+		t.Errorf("Expected only the filtered count on the query's handle, got %v", base.Queries())
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	if result.RecordsTotal != 100 || result.RecordsFiltered != 3 || rows[0]["name"] != "John" {
+		t.Errorf("Expected total from the replica, filtered from the query, and rows from the fetch handle, got %+v", result)
+	}
+}
+
+func TestOfReturnFooterAggregate(t *testing.T) {
+	result := userResult(
+		TestUser{ID: 1, Name: "John", Email: "john@example.com"},
+	)
+
+	// newFooterDB answers the footer aggregate query with footer and every
+	// other query like a plain fakeDB counting count rows.
+	newFooterDB := func(t *testing.T, count int64, footer fakeResult) (*fakeDB, *gorm.DB) {
+		fake := &fakeDB{}
+		fake.handler = func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+			switch {
+			case isCountQuery(query):
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{count}}}, nil
+			case isFooterQuery(query):
+				return footer, nil
+			}
+			return result, nil
+		}
+		return fake, newFakeGormDB(t, fake)
+	}
+
+	t.Run("Aggregates the filtered set", func(t *testing.T) {
+		fake, db := newFooterDB(t, 3, fakeResult{
+			columns: []string{"total_ids", "rows"},
+			rows:    [][]driver.Value{{[]byte("6"), int64(3)}},
+		})
+		c, _ := newTestContext("start=0&length=1&search[value]=jo")
+
+		var users []TestUser
+		opts := NewOptions().WithFooterAggregate("total_ids", "SUM(id)").WithFooterAggregate("rows", "COUNT(*)")
+		res, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "SELECT SUM(id) AS total_ids, COUNT(*) AS rows FROM `test_users` WHERE LOWER(name) LIKE LOWER(?)"
+		if got := footerQuery(fake); got != expected {
+			t.Errorf("Unexpected footer query:\n got  %s\n want %s", got, expected)
+		}
+
+		if res.Footer["total_ids"] != int64(6) || res.Footer["rows"] != int64(3) {
+			t.Errorf("Expected footer {total_ids: 6, rows: 3}, got %v", res.Footer)
+		}
+		if len(res.Data.([]map[string]interface{})) != 1 {
+			t.Errorf("Expected the page to be unaffected, got %v", res.Data)
+		}
+	})
+
+	t.Run("Grouped queries aggregate through a subquery", func(t *testing.T) {
+		fake, db := newFooterDB(t, 2, fakeResult{
+			columns: []string{"total_ids"},
+			rows:    [][]driver.Value{{int64(5)}},
+		})
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		query := db.Table("test_users").Select("name, COUNT(*) AS total").Group("name")
+		opts := NewOptions().WithGroupedCount(true).WithFooterAggregate("total_ids", "SUM(total)")
+		res, err := OfReturn(c, query, &users, nil, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "SELECT SUM(total) AS total_ids FROM (SELECT name, COUNT(*) AS total FROM `test_users` GROUP BY `name`) AS dt_footer"
+		if got := footerQuery(fake); got != expected {
+			t.Errorf("Unexpected footer query:\n got  %s\n want %s", got, expected)
+		}
+		if res.Footer["total_ids"] != int64(5) {
+			t.Errorf("Expected footer {total_ids: 5}, got %v", res.Footer)
+		}
+	})
+
+	t.Run("Invalid output key is rejected", func(t *testing.T) {
+		db := newFakeGormDB(t, &fakeDB{count: 1, result: result})
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		opts := NewOptions().WithFooterAggregate("total; DROP", "SUM(id)")
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected a ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("No footer by default", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: result}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("")
+
+		var users []TestUser
+		res, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if res.Footer != nil || footerQuery(fake) != "" {
+			t.Errorf("Expected no footer, got %v", res.Footer)
+		}
+	})
+}
+
+// isFooterQuery reports whether a query is a footer aggregate query.
+func isFooterQuery(query string) bool {
+	return strings.Contains(query, " AS total_ids")
+}
+
+// footerQuery returns the footer aggregate query recorded by fake, if any.
+func footerQuery(fake *fakeDB) string {
+	for _, q := range fake.Queries() {
+		if isFooterQuery(q.SQL) {
+			return q.SQL
+		}
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package datatables
+
+import (
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// pageRequest is a DataTables request resolved against its columns and
+// options. OfReturn, OfParams, ExplainPlan, and SelectIDs build their queries
+// from it, so the plan explains the SQL that actually runs and the selected
+// ids are those of the rows the table shows.
+type pageRequest struct {
+	params          dto.Params
+	searchable      []string
+	orderable       map[string]string
+	opts            Options
+	model           interface{}
+	requestedLength int    // Page length requested before the result limit
+	inMemorySearch  bool   // The global search runs in Go on the fetched set
+	computedOrder   string // Computed column ordered in Go, if any
+	fetchAll        bool   // The whole filtered set is fetched and paginated in Go
+}
+
+// resolveRequest resolves and validates the searchable and orderable columns
+// (hiding those denied by the ColumnACL), detects the dialect, and applies the
+// search value and pagination limits. The Gin context may be nil.
+func resolveRequest(
+	c *gin.Context,
+	query *gorm.DB,
+	model interface{},
+	params dto.Params,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+) (pageRequest, error) {
+	searchable, err := resolveColumns(query, model, searchable, orderable, opts)
+	if err != nil {
+		return pageRequest{}, err
+	}
+
+	// Columns hidden by the ACL can be neither searched nor ordered by
+	if opts.ColumnACL != nil {
+		searchable, orderable = aclColumns(aclContext(c), searchable, orderable, opts)
+	}
+	if err := validateIndexBase(opts); err != nil {
+		return pageRequest{}, err
+	}
+
+	// Warn about display-only removals that affect ordering
+	if opts.Logger != nil {
+		for _, warning := range removedOrderWarnings(orderable, opts) {
+			warnf(opts, "%s", warning)
+		}
+	}
+
+	// Detect the SQL dialect once for all query builders
+	opts.Dialect = resolveDialect(query, opts)
+
+	// Apply the initial search and length cap
+	if params.Search, err = resolveSearch(params.Search, opts); err != nil {
+		return pageRequest{}, err
+	}
+
+	// A search without searchable columns is ignored, which is rarely intended
+	if params.Search != "" && len(searchable) == 0 {
+		warnf(opts, "search value ignored: no searchable columns are configured (pass searchable columns, or see WithAutoSearchable and WithSearchFallback)")
+	}
+
+	// Guard against deep pagination
+	if params.Start, err = limitOffset(params.Start, opts); err != nil {
+		return pageRequest{}, err
+	}
+
+	// Hard-cap the fetched rows, even for length=-1
+	requestedLength := params.Length
+	params.Length = limitResult(params.Length, opts)
+
+	// In-memory search fetches the whole set and filters it in Go
+	inMemorySearch := opts.InMemorySearch && params.Search != "" && len(searchable) > 0
+
+	// Ordering by a computed column sorts in Go, over the whole set if requested
+	computedOrder := computedOrderColumn(params, orderable, opts)
+	if computedOrder != "" && opts.ColumnACL != nil && !opts.ColumnACL(aclContext(c), computedOrder) {
+		computedOrder = ""
+	}
+
+	return pageRequest{
+		params:          params,
+		searchable:      searchable,
+		orderable:       orderable,
+		opts:            opts,
+		model:           model,
+		requestedLength: requestedLength,
+		inMemorySearch:  inMemorySearch,
+		computedOrder:   computedOrder,
+		fetchAll:        inMemorySearch || (computedOrder != "" && opts.ComputedOrderFullSet),
+	}, nil
+}
+
+// filter applies the request's filtering to a new session of query: the
+// global search (unless it runs in memory), the per-column searches, the
+// declarative filters, and the HAVING conditions. groupSearch wraps the
+// search in parentheses for callers adding conditions of their own.
+//
+// Returns the query and whether any filter was applied.
+func (r pageRequest) filter(c *gin.Context, query *gorm.DB, groupSearch bool) (*gorm.DB, bool) {
+	params, opts := r.params, r.opts
+	filteredQuery := query.Session(&gorm.Session{})
+	filterApplied := false
+
+	// Apply filtering (global search)
+	if params.Search != "" && len(r.searchable) > 0 && !r.inMemorySearch {
+		// Group the OR chain when further conditions follow (the keyset
+		// condition is added by applyPage)
+		if groupSearch || len(opts.ParamFilters) > 0 || len(params.ColumnSearches) > 0 || opts.CursorColumn != "" {
+			filteredQuery = applyGroupedSearch(filteredQuery, r.searchable, params.Search, opts)
+		} else {
+			filteredQuery = applySearch(filteredQuery, r.searchable, params.Search, opts)
+		}
+		filterApplied = true
+	}
+
+	// Apply per-column searches
+	var columnFiltered bool
+	if filteredQuery, columnFiltered = applyColumnSearches(filteredQuery, r.searchable, params.ColumnSearches, opts); columnFiltered {
+		filterApplied = true
+	}
+
+	// Apply declarative filters from the request parameters
+	if len(opts.ParamFilters) > 0 {
+		var paramFiltered bool
+		if filteredQuery, paramFiltered = applyParamFilters(paramValues(c, opts), filteredQuery, opts.ParamFilters); paramFiltered {
+			filterApplied = true
+		}
+	}
+
+	// Filter grouped results on their aggregates
+	var havingApplied bool
+	if filteredQuery, havingApplied = applyHaving(filteredQuery, opts.Having); havingApplied {
+		filterApplied = true
+	}
+
+	return filteredQuery, filterApplied
+}
+
+// page applies the request's ordering, pagination, and computed columns to
+// the filtered query. Pagination is left to Go when the whole set is fetched.
+func (r pageRequest) page(query *gorm.DB) (*gorm.DB, error) {
+	return applyPage(query, r.model, r.params, r.orderable, r.opts, !r.fetchAll)
+}
@@ -1,12 +1,29 @@
 package datatables
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
 	"github.com/gin-gonic/gin"
 )
 
+// Request flags used by JSONAuto to select the response format
+const (
+	formatQueryParam = "format"
+	formatHeader     = "X-DataTables-Format"
+	rawFormat        = "raw"
+)
+
+// maxJSONPCallbackLength caps the length of JSONP callback names
+const maxJSONPCallbackLength = 128
+
+// pureJSONKey marks a Gin context whose responses skip HTML escaping (see Options.WithPureJSON)
+const pureJSONKey = "datatables.pureJSON"
+
 // JSON is a convenience helper that sends a standardized DataTables response.
 // It wraps the DataTables result in a SuccessResponse structure and sends it
 // as JSON with HTTP 200 OK status.
@@ -23,7 +40,135 @@ import (
 //   }
 //   datatables.JSON(c, result)
 func JSON(c *gin.Context, res dto.Datatables) {
-	dto.ResponseDatatables(c, http.StatusOK, res, "success")
+	JSONStatus(c, http.StatusOK, res)
+}
+
+// JSONStatus sends a standardized DataTables response with a custom HTTP status code.
+// The response uses the same SuccessResponse envelope as JSON.
+// Large responses may be gzip-compressed (see SetGzipThreshold).
+//
+// Parameters:
+//   - c: Gin context
+//   - status: HTTP status code (e.g., 200, 206)
+//   - res: DataTables response containing draw, records total/filtered, and data
+//
+// Example:
+//   datatables.JSONStatus(c, http.StatusPartialContent, result)
+func JSONStatus(c *gin.Context, status int, res dto.Datatables) {
+	envelope := dto.SuccessResponse{Success: true, Message: "success", Data: res}
+	if writeGzipJSON(c, status, envelope) {
+		return
+	}
+	if usePureJSON(c) {
+		c.PureJSON(status, envelope)
+		return
+	}
+	dto.ResponseDatatables(c, status, res, "success")
+}
+
+// JSONRaw sends the bare DataTables response structure without the
+// SuccessResponse envelope, as expected by the jQuery DataTables plugin.
+//
+// Example:
+//   datatables.JSONRaw(c, result)
+//   // {"draw": 1, "recordsTotal": 10, "recordsFiltered": 10, "data": [...]}
+func JSONRaw(c *gin.Context, res dto.Datatables) {
+	if writeGzipJSON(c, http.StatusOK, res) {
+		return
+	}
+	if usePureJSON(c) {
+		c.PureJSON(http.StatusOK, res)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// usePureJSON reports whether OfReturn ran with Options.WithPureJSON on this context.
+func usePureJSON(c *gin.Context) bool {
+	return c.GetBool(pureJSONKey)
+}
+
+// marshalJSON encodes v like json.Marshal, optionally leaving HTML characters unescaped.
+func marshalJSON(v interface{}, escapeHTML bool) ([]byte, error) {
+	if escapeHTML {
+		return json.Marshal(v)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// JSONAuto chooses between JSONRaw and JSON based on the request, so one
+// endpoint can serve both the jQuery plugin and custom clients.
+//
+// The bare DataTables structure is sent when the "format" query parameter
+// or the "X-DataTables-Format" header equals "raw"; otherwise the response
+// is wrapped in the SuccessResponse envelope.
+//
+// Example:
+//   // GET /api/users?format=raw -> bare DataTables JSON
+//   // GET /api/users            -> SuccessResponse envelope
+//   datatables.JSONAuto(c, result)
+func JSONAuto(c *gin.Context, res dto.Datatables) {
+	if isRawFormat(c) {
+		JSONRaw(c, res)
+		return
+	}
+	JSON(c, res)
+}
+
+// isRawFormat reports whether the request asks for the bare DataTables format.
+func isRawFormat(c *gin.Context) bool {
+	format := c.Query(formatQueryParam)
+	if format == "" {
+		format = c.GetHeader(formatHeader)
+	}
+	return strings.EqualFold(strings.TrimSpace(format), rawFormat)
+}
+
+// jsonpCallbackPattern matches safe JSONP callback names: JavaScript
+// identifiers, optionally dotted (e.g. "jQuery123_456" or "app.tables.load").
+var jsonpCallbackPattern = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*(\.[a-zA-Z_$][a-zA-Z0-9_$]*)*$`)
+
+// JSONP sends the bare DataTables response wrapped in a JSONP callback, for
+// legacy cross-domain setups (DataTables' ajax dataType "jsonp"). The response
+// is served as application/javascript.
+//
+// The callback name is validated to prevent script injection: only
+// JavaScript identifiers, optionally dotted, are accepted. An invalid
+// callback responds with 400 Bad Request in the JSONError format.
+//
+// Parameters:
+//   - c: Gin context
+//   - callback: The callback name, usually the request's "callback" parameter
+//   - res: DataTables response containing draw, records total/filtered, and data
+//
+// Example:
+//   datatables.JSONP(c, c.Query("callback"), result)
+//   // /**/jQuery123({"draw":1,"recordsTotal":10,...});
+func JSONP(c *gin.Context, callback string, res dto.Datatables) {
+	if len(callback) > maxJSONPCallbackLength || !jsonpCallbackPattern.MatchString(callback) {
+		JSONError(c, http.StatusBadRequest, "invalid JSONP callback name")
+		return
+	}
+
+	body, err := json.Marshal(res)
+	if err != nil {
+		JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The leading comment guards against content sniffing attacks on the callback
+	payload := make([]byte, 0, len(body)+len(callback)+8)
+	payload = append(payload, "/**/"+callback+"("...)
+	payload = append(payload, body...)
+	payload = append(payload, ");"...)
+	c.Data(http.StatusOK, "application/javascript; charset=utf-8", payload)
 }
 
 // JSONError is a convenience helper for sending error responses in a consistent format.
@@ -40,3 +185,25 @@ func JSONError(c *gin.Context, statusCode int, message string) {
 		Errors:  message,
 	})
 }
+
+// RespondError sends an error response through the responder configured with
+// SetErrorResponder, falling back to JSONError when none is set.
+//
+// Parameters:
+//   - c: Gin context
+//   - statusCode: HTTP status code (e.g., 400, 500)
+//   - err: The error to report
+//
+// Example:
+//   result, err := datatables.OfReturn(c, query, &users, searchable, orderable, opts)
+//   if err != nil {
+//       datatables.RespondError(c, http.StatusInternalServerError, err)
+//       return
+//   }
+func RespondError(c *gin.Context, statusCode int, err error) {
+	if responder := errorResponder(); responder != nil {
+		responder(c, statusCode, err)
+		return
+	}
+	JSONError(c, statusCode, err.Error())
+}
@@ -0,0 +1,265 @@
+package datatables
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+)
+
+func TestJSONAuto(t *testing.T) {
+	res := dto.Datatables{Draw: 1, RecordsTotal: 1, RecordsFiltered: 1, Data: []map[string]interface{}{{"id": 1}}}
+
+	tests := []struct {
+		name    string
+		query   string
+		header  string
+		wantRaw bool
+	}{
+		{"Default envelope", "", "", false},
+		{"Raw via query flag", "format=raw", "", true},
+		{"Raw via header", "", "RAW", true},
+		{"Unknown format", "format=xml", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newTestContext(tt.query)
+			if tt.header != "" {
+				c.Request.Header.Set(formatHeader, tt.header)
+			}
+
+			JSONAuto(c, res)
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+
+			_, hasDraw := body["draw"]
+			_, hasSuccess := body["success"]
+			if tt.wantRaw && (!hasDraw || hasSuccess) {
+				t.Errorf("Expected raw DataTables body, got %s", w.Body.String())
+			}
+			if !tt.wantRaw && (hasDraw || !hasSuccess) {
+				t.Errorf("Expected SuccessResponse envelope, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONP(t *testing.T) {
+	res := dto.Datatables{Draw: 2, RecordsTotal: 1, RecordsFiltered: 1, Data: []map[string]interface{}{{"name": "</script><script>alert(1)</script>"}}}
+
+	t.Run("Wraps the response in the callback", func(t *testing.T) {
+		c, w := newTestContext("")
+
+		JSONP(c, "jQuery123_456", res)
+
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/javascript; charset=utf-8" {
+			t.Fatalf("Expected a 200 JavaScript response, got %d %q", w.Code, w.Header().Get("Content-Type"))
+		}
+
+		body := w.Body.String()
+		prefix, suffix := "/**/jQuery123_456(", ");"
+		if !strings.HasPrefix(body, prefix) || !strings.HasSuffix(body, suffix) {
+			t.Fatalf("Expected the response wrapped in the callback, got %s", body)
+		}
+
+		var decoded dto.Datatables
+		if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(body, prefix), suffix)), &decoded); err != nil || decoded.Draw != 2 {
+			t.Errorf("Expected the bare DataTables JSON, got %s (err %v)", body, err)
+		}
+		if strings.Contains(body, "</script>") {
+			t.Errorf("Expected markup in the data to be escaped, got %s", body)
+		}
+	})
+
+	t.Run("Dotted callbacks are accepted", func(t *testing.T) {
+		c, w := newTestContext("")
+
+		JSONP(c, "app.tables.$load", res)
+
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "/**/app.tables.$load(") {
+			t.Errorf("Expected a dotted callback to be accepted, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	for _, callback := range []string{"", "alert(1)//", "cb;alert(1)", "1cb", "a..b", "<script>", strings.Repeat("a", 200)} {
+		t.Run("Rejects "+callback, func(t *testing.T) {
+			c, w := newTestContext("")
+
+			JSONP(c, callback, res)
+
+			if w.Code != http.StatusBadRequest || strings.Contains(w.Body.String(), "recordsTotal") {
+				t.Errorf("Expected callback %q to be rejected, got %d %s", callback, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	t.Run("Default JSONError format", func(t *testing.T) {
+		c, w := newTestContext("")
+
+		RespondError(c, http.StatusBadRequest, errors.New("bad column"))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+		var body dto.SuccessResponse
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if body.Success || body.Message != "bad column" {
+			t.Errorf("Unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("Custom responder", func(t *testing.T) {
+		SetErrorResponder(func(c *gin.Context, status int, err error) {
+			c.JSON(status, gin.H{"code": status, "message": err.Error(), "details": nil})
+		})
+		defer SetErrorResponder(nil)
+
+		c, w := newTestContext("")
+
+		RespondError(c, http.StatusInternalServerError, errors.New("boom"))
+
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if body["code"] != float64(500) || body["message"] != "boom" {
+			t.Errorf("Expected custom error shape, got %s", w.Body.String())
+		}
+		if _, ok := body["success"]; ok {
+			t.Errorf("Default envelope should not be used, got %s", w.Body.String())
+		}
+	})
+}
+
+func TestJSONGzip(t *testing.T) {
+	SetGzipThreshold(1024)
+	defer SetGzipThreshold(0)
+
+	rows := make([]map[string]interface{}, 0, 100)
+	for i := 0; i < 100; i++ {
+		rows = append(rows, map[string]interface{}{"id": i, "name": strings.Repeat("x", 20)})
+	}
+	large := dto.Datatables{Draw: 1, RecordsTotal: 100, RecordsFiltered: 100, Data: rows}
+	small := dto.Datatables{Draw: 1, Data: []map[string]interface{}{{"id": 1}}}
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		res            dto.Datatables
+		wantGzip       bool
+	}{
+		{"Large payload with gzip accepted", "gzip, deflate, br", large, true},
+		{"Large payload without Accept-Encoding", "", large, false},
+		{"Large payload with gzip refused", "gzip;q=0, deflate", large, false},
+		{"Small payload", "gzip", small, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newTestContext("")
+			c.Request.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+			JSON(c, tt.res)
+
+			if got := w.Header().Get("Content-Encoding") == "gzip"; got != tt.wantGzip {
+				t.Fatalf("Expected gzip=%v, got Content-Encoding %q", tt.wantGzip, w.Header().Get("Content-Encoding"))
+			}
+
+			var body io.Reader = w.Body
+			if tt.wantGzip {
+				gz, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("Invalid gzip body: %v", err)
+				}
+				body = gz
+			}
+
+			var decoded dto.SuccessResponse
+			if err := json.NewDecoder(body).Decode(&decoded); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+			if !decoded.Success || w.Code != http.StatusOK {
+				t.Errorf("Unexpected response: %d %+v", w.Code, decoded)
+			}
+		})
+	}
+}
+
+func TestJSONPure(t *testing.T) {
+	result := userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"})
+	action := func(row map[string]interface{}) interface{} {
+		return `<a href="/users/1">Edit</a> & more`
+	}
+
+	tests := []struct {
+		name     string
+		pure     bool
+		respond  func(c *gin.Context, res dto.Datatables)
+		expected string
+	}{
+		{"JSON escapes HTML by default", false, JSON, `\u003ca href=\"/users/1\"\u003eEdit\u003c/a\u003e \u0026 more`},
+		{"JSON with PureJSON", true, JSON, `<a href=\"/users/1\">Edit</a> & more`},
+		{"JSONRaw escapes HTML by default", false, JSONRaw, `\u003ca href=\"/users/1\"\u003eEdit\u003c/a\u003e \u0026 more`},
+		{"JSONRaw with PureJSON", true, JSONRaw, `<a href=\"/users/1\">Edit</a> & more`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeGormDB(t, &fakeDB{count: 1, result: result})
+			c, w := newTestContext("")
+
+			var users []TestUser
+			opts := NewOptions().Add("action", action).WithPureJSON(tt.pure)
+			res, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			tt.respond(c, res)
+
+			if !strings.Contains(w.Body.String(), `"action":"`+tt.expected+`"`) {
+				t.Errorf("Expected action %s, got %s", tt.expected, w.Body.String())
+			}
+
+			var decoded map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+		})
+	}
+
+	t.Run("Gzip payload with PureJSON", func(t *testing.T) {
+		SetGzipThreshold(1)
+		defer SetGzipThreshold(0)
+
+		db := newFakeGormDB(t, &fakeDB{count: 1, result: result})
+		c, w := newTestContext("")
+		c.Request.Header.Set("Accept-Encoding", "gzip")
+
+		var users []TestUser
+		res, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().Add("action", action).WithPureJSON(true))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		JSONRaw(c, res)
+
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("Invalid gzip body: %v", err)
+		}
+		body, _ := io.ReadAll(gz)
+		if !strings.Contains(string(body), `<a href=\"/users/1\">Edit</a> & more`) {
+			t.Errorf("Expected unescaped HTML, got %s", body)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package datatables
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+// withRetry runs fn, retrying it on transient errors as configured by
+// opts.RetryAttempts, opts.RetryBackoff, and opts.RetryIf.
+//
+// The backoff doubles after each failed attempt. Retries stop as soon as the
+// context is done, and errors caused by cancellation or an exceeded deadline
+// are never retried. Returns the last error when all attempts fail.
+//
+// fn must run its query on a new session (query.Session(&gorm.Session{})),
+// since GORM keeps the error of a failed call on a chained statement.
+func withRetry(ctx context.Context, opts Options, fn func() error) error {
+	attempts := opts.RetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	isTransient := opts.RetryIf
+	if isTransient == nil {
+		isTransient = isTransientError
+	}
+
+	backoff := opts.RetryBackoff
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		if attempt >= attempts || isContextError(err) || !isTransient(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientError is the default retry matcher. It matches dropped
+// connections and network errors that are typically resolved by retrying.
+func isTransientError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isContextError reports whether err was caused by context cancellation or deadline.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,113 @@
+package datatables
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	opts := NewOptions().WithRetry(3, time.Millisecond)
+
+	t.Run("Succeeds after a transient failure", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), opts, func() error {
+			calls++
+			if calls == 1 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		})
+
+		if err != nil || calls != 2 {
+			t.Errorf("Expected success on second attempt, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("Stops after the configured attempts", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), opts, func() error {
+			calls++
+			return io.ErrUnexpectedEOF
+		})
+
+		if !errors.Is(err, io.ErrUnexpectedEOF) || calls != 3 {
+			t.Errorf("Expected 3 failed attempts, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("Does not retry non-transient or context errors", func(t *testing.T) {
+		for _, failure := range []error{errors.New("syntax error"), context.Canceled, context.DeadlineExceeded} {
+			calls := 0
+			withRetry(context.Background(), opts, func() error {
+				calls++
+				return failure
+			})
+
+			if calls != 1 {
+				t.Errorf("Expected no retry for %v, got %d calls", failure, calls)
+			}
+		}
+	})
+
+	t.Run("Stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := 0
+		withRetry(ctx, NewOptions().WithRetry(5, time.Hour), func() error {
+			calls++
+			return io.ErrUnexpectedEOF
+		})
+
+		if calls != 1 {
+			t.Errorf("Expected retries to stop on a done context, got %d calls", calls)
+		}
+	})
+
+	t.Run("Custom matcher", func(t *testing.T) {
+		flaky := errors.New("connection reset by peer")
+		calls := 0
+		err := withRetry(context.Background(), opts.WithRetryIf(func(err error) bool { return err == flaky }), func() error {
+			calls++
+			if calls == 1 {
+				return flaky
+			}
+			return nil
+		})
+
+		if err != nil || calls != 2 {
+			t.Errorf("Expected custom matcher to retry, got err=%v calls=%d", err, calls)
+		}
+	})
+}
+
+func TestOfReturnRetry(t *testing.T) {
+	failed := false
+	fake := &fakeDB{}
+	fake.handler = func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+		if !isCountQuery(query) {
+			if !failed {
+				failed = true
+				return fakeResult{}, io.ErrUnexpectedEOF
+			}
+			return userResult(TestUser{ID: 1, Name: "John"}), nil
+		}
+		return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}, nil
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("")
+
+	var users []TestUser
+	result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithRetry(2, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+
+	if rows := result.Data.([]map[string]interface{}); len(rows) != 1 {
+		t.Errorf("Expected 1 row, got %d", len(rows))
+	}
+}
@@ -0,0 +1,352 @@
+package datatables
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"gorm.io/gorm"
+)
+
+// defaultMaxSearchLength is the maximum search length unless
+// overridden with Options.WithMaxSearchLength
+const defaultMaxSearchLength = 255
+
+// limitSearch enforces opts.MaxSearchLength on the search value, truncating
+// it or, with opts.RejectLongSearch, returning a ValidationError.
+func limitSearch(value string, opts Options) (string, error) {
+	limit := opts.MaxSearchLength
+	if limit == 0 {
+		limit = defaultMaxSearchLength
+	}
+
+	runes := []rune(value)
+	if limit < 0 || len(runes) <= limit {
+		return value, nil
+	}
+
+	if opts.RejectLongSearch {
+		return "", &ValidationError{
+			Field:   "search[value]",
+			Message: "search value exceeds the maximum length of " + strconv.Itoa(limit) + " characters",
+		}
+	}
+	return string(runes[:limit]), nil
+}
+
+// searchCondition is a single parameterized condition of the global search OR group.
+type searchCondition struct {
+	sql  string
+	args []interface{}
+}
+
+// applySearch adds global search conditions to the query.
+// Uses OR conditions across all searchable columns with case-insensitive matching.
+//
+// Search values containing double quotes are split into terms (see searchTerms):
+// each term must match at least one column, so `"john doe" admin` becomes
+//   (name LIKE %john doe% OR email LIKE %john doe%) AND (name LIKE %admin% OR email LIKE %admin%)
+//
+// Options.SearchCallback, when set, replaces all of the above.
+func applySearch(query *gorm.DB, searchable []string, searchValue string, opts Options) *gorm.DB {
+	if opts.SearchCallback != nil {
+		return opts.SearchCallback(query, searchable, searchValue)
+	}
+
+	dialect := resolveDialect(query, opts)
+
+	terms := searchTerms(searchValue)
+	if terms == nil {
+		conditions := buildSearchConditions(searchable, searchValue, opts, dialect)
+		if len(conditions) == 0 {
+			// No column can match the search value (e.g., only boolean columns)
+			return query.Where("1 = 0")
+		}
+
+		for i, cond := range conditions {
+			if i == 0 {
+				query = query.Where(cond.sql, cond.args...)
+			} else {
+				query = query.Or(cond.sql, cond.args...)
+			}
+		}
+		return query
+	}
+
+	for _, term := range terms {
+		conditions := buildSearchConditions(searchable, term, opts, dialect)
+		if len(conditions) == 0 {
+			return query.Where("1 = 0")
+		}
+
+		group := query.Session(&gorm.Session{NewDB: true})
+		for i, cond := range conditions {
+			if i == 0 {
+				group = group.Where(cond.sql, cond.args...)
+			} else {
+				group = group.Or(cond.sql, cond.args...)
+			}
+		}
+		query = query.Where(group)
+	}
+	return query
+}
+
+// applyGroupedSearch applies the search as a single parenthesized condition,
+// so its OR chain cannot swallow conditions added after it.
+func applyGroupedSearch(query *gorm.DB, searchable []string, searchValue string, opts Options) *gorm.DB {
+	group := query.Session(&gorm.Session{NewDB: true})
+	return query.Where(applySearch(group, searchable, searchValue, opts))
+}
+
+// applyColumnSearches adds a condition for every per-column search value.
+// Only whitelisted columns are searched: SearchableMap keys (resolved to their
+// database column) and the searchable columns; other columns are ignored.
+//
+// Values on Options.OperatorColumns may start with a comparison operator
+// (see parseOperatorValue); other values are matched like the global search.
+//
+// Returns the query and whether any condition was applied.
+func applyColumnSearches(query *gorm.DB, searchable []string, searches []dto.ColumnSearch, opts Options) (*gorm.DB, bool) {
+	applied := false
+	for _, search := range searches {
+		col, ok := opts.SearchableMap[search.Column]
+		if !ok {
+			if !containsString(searchable, search.Column) {
+				continue
+			}
+			col = search.Column
+		}
+
+		if containsString(opts.OperatorColumns, search.Column) || containsString(opts.OperatorColumns, col) {
+			if op, operand, ok := parseOperatorValue(search.Value); ok {
+				query = query.Where(col+" "+op+" ?", operand)
+				applied = true
+				continue
+			}
+		}
+
+		conditions := buildSearchConditions([]string{col}, search.Value, opts, resolveDialect(query, opts))
+		if len(conditions) == 0 {
+			// The column cannot match the value (e.g., a boolean column)
+			return query.Where("1 = 0"), true
+		}
+		query = query.Where(conditions[0].sql, conditions[0].args...)
+		applied = true
+	}
+	return query, applied
+}
+
+// searchOperators lists the comparison operators recognized by parseOperatorValue,
+// two-character operators first so ">=" is not read as ">"
+var searchOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// parseOperatorValue splits a search value such as ">100" or "<= 2.5" into a
+// comparison operator and a numeric operand (int64 or float64). "!=" is
+// rendered as the standard "<>". Returns false if the value does not start with
+// an operator or the operand is not a number.
+func parseOperatorValue(value string) (op string, operand interface{}, ok bool) {
+	value = strings.TrimSpace(value)
+	for _, candidate := range searchOperators {
+		if !strings.HasPrefix(value, candidate) {
+			continue
+		}
+
+		number := strings.TrimSpace(value[len(candidate):])
+		if candidate == "!=" {
+			candidate = "<>"
+		}
+		if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+			return candidate, n, true
+		}
+		if f, err := strconv.ParseFloat(number, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return candidate, f, true
+		}
+		return "", nil, false
+	}
+	return "", nil, false
+}
+
+// searchTerms splits a search value containing double quotes into terms:
+// quoted substrings are exact phrases, and the unquoted text around them is
+// split into words. An unterminated quote extends to the end of the value.
+//
+// Returns nil when the value contains no quotes, so it is searched as a whole.
+//
+// Example:
+//   searchTerms(`"john doe" admin`) // ["john doe", "admin"]
+func searchTerms(value string) []string {
+	if !strings.Contains(value, `"`) {
+		return nil
+	}
+
+	terms := []string{}
+	for i, part := range strings.Split(value, `"`) {
+		if i%2 == 1 {
+			// Inside quotes: an exact phrase
+			if phrase := strings.TrimSpace(part); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		terms = append(terms, strings.Fields(part)...)
+	}
+	return terms
+}
+
+// buildSearchConditions builds one condition per searchable column.
+// Columns listed in opts.BoolSearchColumns use "col = ?" when the value is one
+// of their tokens and are skipped otherwise.
+// Columns listed in opts.DateSearchColumns are formatted as text (see dateSearchExpr),
+// columns listed in opts.ExactColumns use "col = ?", all others use
+// a case-insensitive "LOWER(col) LIKE LOWER(?)" substring match.
+func buildSearchConditions(searchable []string, searchValue string, opts Options, dialect string) []searchCondition {
+	conditions := make([]searchCondition, 0, len(searchable))
+
+	for _, col := range searchable {
+		expr, args := searchColumnExpr(col, opts)
+		value := searchValue
+
+		// Normalize the search term the same way as the column
+		if replacements, ok := opts.SearchNormalizers[col]; ok {
+			value = normalizeSearchValue(value, replacements)
+		}
+
+		// Boolean columns match configured tokens by equality
+		if tokens, ok := opts.BoolSearchColumns[col]; ok {
+			if match, ok := boolSearchValue(tokens, value); ok {
+				conditions = append(conditions, searchCondition{
+					sql:  expr + " = ?",
+					args: append(args, match),
+				})
+			}
+			continue
+		}
+
+		// Date columns are formatted as text before matching
+		if containsString(opts.DateSearchColumns, col) {
+			dateExpr, dateArgs := dateSearchExpr(dialect, expr, opts.DateSearchFormat)
+			conditions = append(conditions, searchCondition{
+				sql:  dateExpr + " LIKE ?",
+				args: append(append(args, dateArgs...), "%"+value+"%"),
+			})
+			continue
+		}
+
+		// Identifier columns use equality instead of a substring match
+		if containsString(opts.ExactColumns, col) {
+			conditions = append(conditions, searchCondition{
+				sql:  expr + " = ?",
+				args: append(args, value),
+			})
+			continue
+		}
+
+		// PostgreSQL folds diacritics with the unaccent extension
+		if opts.UnaccentSearch && dialect == dialectPostgres {
+			conditions = append(conditions, searchCondition{
+				sql:  "LOWER(unaccent(" + expr + ")) LIKE LOWER(unaccent(?))",
+				args: append(args, "%"+value+"%"),
+			})
+			continue
+		}
+
+		conditions = append(conditions, searchCondition{
+			sql:  "LOWER(" + expr + ") LIKE LOWER(?)",
+			args: append(args, "%"+value+"%"),
+		})
+	}
+
+	return conditions
+}
+
+// boolSearchValue resolves a search value to the boolean it stands for.
+// Returns false for ok if the value matches none of the tokens.
+func boolSearchValue(tokens BoolTokens, value string) (match bool, ok bool) {
+	value = strings.TrimSpace(value)
+	for _, token := range tokens.True {
+		if strings.EqualFold(token, value) {
+			return true, true
+		}
+	}
+	for _, token := range tokens.False {
+		if strings.EqualFold(token, value) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// searchColumnExpr returns the SQL expression searched for a column.
+// Columns with a registered normalizer are wrapped in nested REPLACE calls,
+// with the replacement strings passed as parameters:
+//   REPLACE(REPLACE(phone, ?, ?), ?, ?)
+func searchColumnExpr(col string, opts Options) (string, []interface{}) {
+	replacements, ok := opts.SearchNormalizers[col]
+	if !ok {
+		return col, nil
+	}
+
+	expr := col
+	args := make([]interface{}, 0, len(replacements)*2)
+	for _, from := range sortedKeys(replacements) {
+		expr = "REPLACE(" + expr + ", ?, ?)"
+		args = append(args, from, replacements[from])
+	}
+
+	return expr, args
+}
+
+// normalizeSearchValue applies the column replacements to the search term,
+// in the same order as the SQL expression.
+func normalizeSearchValue(value string, replacements map[string]string) string {
+	for _, from := range sortedKeys(replacements) {
+		value = strings.ReplaceAll(value, from, replacements[from])
+	}
+	return value
+}
+
+// sortedKeys returns the map keys in sorted order, keeping generated SQL deterministic.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dateSearchExpr returns a dialect-specific expression formatting a date column
+// as text, so the search term is matched against a predictable representation.
+// The format is passed as a parameter and uses the dialect's own syntax;
+// when empty, an ISO-like "YYYY-MM-DD HH:MM:SS" format is used.
+//
+// Dialect differences:
+//   - postgres:  TO_CHAR(col, 'YYYY-MM-DD HH24:MI:SS')
+//   - mysql:     DATE_FORMAT(col, '%Y-%m-%d %H:%i:%s')
+//   - sqlite:    strftime('%Y-%m-%d %H:%M:%S', col)
+//   - sqlserver: FORMAT(col, 'yyyy-MM-dd HH:mm:ss')
+//   - others:    CAST(col AS CHAR(19)), the format is ignored
+func dateSearchExpr(dialect, col, format string) (string, []interface{}) {
+	switch dialect {
+	case dialectPostgres:
+		return "TO_CHAR(" + col + ", ?)", []interface{}{defaultString(format, "YYYY-MM-DD HH24:MI:SS")}
+	case dialectMySQL:
+		return "DATE_FORMAT(" + col + ", ?)", []interface{}{defaultString(format, "%Y-%m-%d %H:%i:%s")}
+	case dialectSQLite:
+		return "strftime(?, " + col + ")", []interface{}{defaultString(format, "%Y-%m-%d %H:%M:%S")}
+	case dialectSQLServer:
+		return "FORMAT(" + col + ", ?)", []interface{}{defaultString(format, "yyyy-MM-dd HH:mm:ss")}
+	}
+	return "CAST(" + col + " AS CHAR(19))", nil
+}
+
+// defaultString returns value, or fallback when value is empty.
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,599 @@
+package datatables
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"gorm.io/gorm"
+)
+
+func TestApplySearch(t *testing.T) {
+	db := newDryRunDB(t)
+
+	sql := dryRunSQL(applySearch(db.Model(&TestUser{}), []string{"name", "email"}, "john", NewOptions()))
+
+	expected := "WHERE LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)"
+	if !strings.Contains(sql, expected) {
+		t.Errorf("Expected %q in %s", expected, sql)
+	}
+}
+
+func TestSearchNormalizer(t *testing.T) {
+	opts := NewOptions().WithSearchNormalizer("phone", map[string]string{"-": "", " ": ""})
+
+	t.Run("Builds nested REPLACE calls", func(t *testing.T) {
+		conditions := buildSearchConditions([]string{"name", "phone"}, "1-23", opts, "")
+
+		if conditions[0].sql != "LOWER(name) LIKE LOWER(?)" {
+			t.Errorf("Unexpected condition for name: %s", conditions[0].sql)
+		}
+		if !reflect.DeepEqual(conditions[0].args, []interface{}{"%1-23%"}) {
+			t.Errorf("Unexpected args for name: %v", conditions[0].args)
+		}
+
+		expectedSQL := "LOWER(REPLACE(REPLACE(phone, ?, ?), ?, ?)) LIKE LOWER(?)"
+		if conditions[1].sql != expectedSQL {
+			t.Errorf("Expected %q, got %q", expectedSQL, conditions[1].sql)
+		}
+
+		expectedArgs := []interface{}{" ", "", "-", "", "%123%"}
+		if !reflect.DeepEqual(conditions[1].args, expectedArgs) {
+			t.Errorf("Expected args %v, got %v", expectedArgs, conditions[1].args)
+		}
+	})
+
+	t.Run("Options are copied", func(t *testing.T) {
+		replacements := map[string]string{"-": ""}
+		base := NewOptions()
+		derived := base.WithSearchNormalizer("phone", replacements)
+		replacements["+"] = ""
+
+		if base.SearchNormalizers != nil {
+			t.Error("Base options should not be modified")
+		}
+		if len(derived.SearchNormalizers["phone"]) != 1 {
+			t.Errorf("Expected replacements to be copied, got %v", derived.SearchNormalizers["phone"])
+		}
+	})
+
+	t.Run("Invalid column is rejected", func(t *testing.T) {
+		err := validateSearchNormalizers(map[string]map[string]string{"phone)--": {"-": ""}})
+		if err == nil {
+			t.Error("Expected validation error")
+		}
+	})
+}
+
+func TestSearchExactColumns(t *testing.T) {
+	db := newDryRunDB(t)
+	opts := NewOptions().WithExactColumns("sku")
+
+	conditions := buildSearchConditions([]string{"name", "sku", "description"}, "AB12", opts, "")
+
+	expected := []searchCondition{
+		{sql: "LOWER(name) LIKE LOWER(?)", args: []interface{}{"%AB12%"}},
+		{sql: "sku = ?", args: []interface{}{"AB12"}},
+		{sql: "LOWER(description) LIKE LOWER(?)", args: []interface{}{"%AB12%"}},
+	}
+	if !reflect.DeepEqual(conditions, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, conditions)
+	}
+
+	sql := dryRunSQL(applySearch(db.Model(&TestUser{}), []string{"name", "sku"}, "AB12", opts))
+	if !strings.Contains(sql, "WHERE LOWER(name) LIKE LOWER(?) OR sku = ?") {
+		t.Errorf("Expected mixed LIKE and equality conditions, got %s", sql)
+	}
+}
+
+func TestSearchDateColumns(t *testing.T) {
+	tests := []struct {
+		dialect string
+		format  string
+		sql     string
+		args    []interface{}
+	}{
+		{"postgres", "", "TO_CHAR(created_at, ?) LIKE ?", []interface{}{"YYYY-MM-DD HH24:MI:SS", "%2024-01%"}},
+		{"postgres", "YYYY-MM", "TO_CHAR(created_at, ?) LIKE ?", []interface{}{"YYYY-MM", "%2024-01%"}},
+		{"mysql", "", "DATE_FORMAT(created_at, ?) LIKE ?", []interface{}{"%Y-%m-%d %H:%i:%s", "%2024-01%"}},
+		{"sqlite", "", "strftime(?, created_at) LIKE ?", []interface{}{"%Y-%m-%d %H:%M:%S", "%2024-01%"}},
+		{"sqlserver", "", "FORMAT(created_at, ?) LIKE ?", []interface{}{"yyyy-MM-dd HH:mm:ss", "%2024-01%"}},
+		{"dummy", "", "CAST(created_at AS CHAR(19)) LIKE ?", []interface{}{"%2024-01%"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect+" "+tt.format, func(t *testing.T) {
+			opts := NewOptions().WithDateSearchColumns(tt.format, "created_at")
+
+			conditions := buildSearchConditions([]string{"name", "created_at"}, "2024-01", opts, tt.dialect)
+
+			if conditions[0].sql != "LOWER(name) LIKE LOWER(?)" {
+				t.Errorf("Non-date column should use LIKE, got %s", conditions[0].sql)
+			}
+			if conditions[1].sql != tt.sql {
+				t.Errorf("Expected %q, got %q", tt.sql, conditions[1].sql)
+			}
+			if !reflect.DeepEqual(conditions[1].args, tt.args) {
+				t.Errorf("Expected args %v, got %v", tt.args, conditions[1].args)
+			}
+		})
+	}
+}
+
+func TestOfReturnSearchableMap(t *testing.T) {
+	type userWithRole struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Role string `json:"role" gorm:"->"`
+	}
+
+	fake := &fakeDB{
+		count: 1,
+		result: fakeResult{
+			columns: []string{"id", "name", "role"},
+			rows:    [][]driver.Value{{int64(1), "John", "admin"}},
+		},
+	}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("search[value]=adm&order[0][column]=role&order[0][dir]=asc")
+
+	query := db.Table("users").
+		Select("users.*, roles.name AS role").
+		Joins("LEFT JOIN roles ON roles.id = users.role_id")
+	opts := NewOptions().
+		WithSearchableMap(map[string]string{"role": "roles.name"}).
+		WithMatchHighlight(true)
+
+	var users []userWithRole
+	result, err := OfReturn(c, query, &users, []string{"users.name"}, map[string]string{"role": "roles.name"}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sql := fake.LastSelect().SQL
+	expected := "WHERE LOWER(users.name) LIKE LOWER(?) OR LOWER(roles.name) LIKE LOWER(?) ORDER BY roles.name asc"
+	if !strings.Contains(sql, expected) {
+		t.Errorf("Expected query to contain %q, got %s", expected, sql)
+	}
+
+	rows := result.Data.([]map[string]interface{})
+	matches := rows[0][matchesKey].(map[string][]MatchPosition)
+	if _, ok := matches["role"]; !ok {
+		t.Errorf("Expected matches under the frontend name 'role', got %v", matches)
+	}
+	if _, ok := matches["name"]; ok {
+		t.Errorf("Expected no match on name, got %v", matches)
+	}
+}
+
+func TestValidateSearchableMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		columns   map[string]string
+		shouldErr bool
+	}{
+		{"Valid mapping", map[string]string{"role": "roles.name"}, false},
+		{"Invalid key", map[string]string{"role;": "roles.name"}, true},
+		{"Invalid value", map[string]string{"role": "roles.name--"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchableMapWith(tt.columns, nil)
+			if (err != nil) != tt.shouldErr {
+				t.Errorf("validateSearchableMapWith() error = %v, shouldErr %v", err, tt.shouldErr)
+			}
+		})
+	}
+}
+
+func TestBuildSearchConditionsBoolSearch(t *testing.T) {
+	opts := NewOptions().WithBoolSearch("is_active", []string{"yes", "active"}, []string{"no", "inactive"})
+
+	tests := []struct {
+		name     string
+		value    string
+		expected []searchCondition
+	}{
+		{"True token", "yes", []searchCondition{
+			{sql: "LOWER(name) LIKE LOWER(?)", args: []interface{}{"%yes%"}},
+			{sql: "is_active = ?", args: []interface{}{true}},
+		}},
+		{"False token is case-insensitive", "No", []searchCondition{
+			{sql: "LOWER(name) LIKE LOWER(?)", args: []interface{}{"%No%"}},
+			{sql: "is_active = ?", args: []interface{}{false}},
+		}},
+		{"Other values skip the column", "john", []searchCondition{
+			{sql: "LOWER(name) LIKE LOWER(?)", args: []interface{}{"%john%"}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := buildSearchConditions([]string{"name", "is_active"}, tt.value, opts, "")
+			if !reflect.DeepEqual(conditions, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, conditions)
+			}
+		})
+	}
+}
+
+func TestOfReturnBoolSearch(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"yes", "WHERE LOWER(name) LIKE LOWER(?) OR is_active = ?"},
+		{"no", "WHERE LOWER(name) LIKE LOWER(?) OR is_active = ?"},
+		{"maybe", "WHERE LOWER(name) LIKE LOWER(?) LIMIT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			fake := &fakeDB{count: 1}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("search[value]=" + tt.value)
+
+			var users []TestUser
+			opts := NewOptions().WithBoolSearch("is_active", []string{"yes"}, []string{"no"})
+			if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if sql := fake.LastSelect().SQL; !strings.Contains(sql, tt.expected) {
+				t.Errorf("Expected query to contain %q, got %s", tt.expected, sql)
+			}
+		})
+	}
+}
+
+func TestApplySearchNoMatchingColumn(t *testing.T) {
+	db := newDryRunDB(t)
+	opts := NewOptions().WithBoolSearch("is_active", []string{"yes"}, []string{"no"})
+
+	sql := dryRunSQL(applySearch(db.Model(&TestUser{}), []string{"is_active"}, "john", opts))
+	if !strings.Contains(sql, "WHERE 1 = 0") {
+		t.Errorf("Expected an always-false condition, got %s", sql)
+	}
+}
+
+func TestLimitSearch(t *testing.T) {
+	long := strings.Repeat("a", 300)
+
+	t.Run("Default cap truncates", func(t *testing.T) {
+		value, err := limitSearch(long, NewOptions())
+		if err != nil || len(value) != defaultMaxSearchLength {
+			t.Errorf("Expected %d characters, got %d (err %v)", defaultMaxSearchLength, len(value), err)
+		}
+	})
+
+	t.Run("Custom cap counts characters", func(t *testing.T) {
+		value, err := limitSearch("héllo wörld", NewOptions().WithMaxSearchLength(5, false))
+		if err != nil || value != "héllo" {
+			t.Errorf("Expected 'héllo', got %q (err %v)", value, err)
+		}
+	})
+
+	t.Run("Reject returns ValidationError", func(t *testing.T) {
+		_, err := limitSearch(long, NewOptions().WithMaxSearchLength(10, true))
+		if _, ok := err.(*ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("Negative disables the cap", func(t *testing.T) {
+		value, err := limitSearch(long, NewOptions().WithMaxSearchLength(-1, true))
+		if err != nil || value != long {
+			t.Errorf("Expected the value unchanged, got %d characters (err %v)", len(value), err)
+		}
+	})
+}
+
+func TestOfReturnLongSearch(t *testing.T) {
+	t.Run("Truncated before building the LIKE", func(t *testing.T) {
+		fake := &fakeDB{count: 1}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=" + strings.Repeat("x", 20))
+
+		var users []TestUser
+		opts := NewOptions().WithMaxSearchLength(4, false)
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if args := fake.LastSelect().Args; len(args) == 0 || args[0] != "%xxxx%" {
+			t.Errorf("Expected truncated search arg, got %v", args)
+		}
+	})
+
+	t.Run("Rejected without querying", func(t *testing.T) {
+		fake := &fakeDB{count: 1}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=" + strings.Repeat("x", 20))
+
+		var users []TestUser
+		opts := NewOptions().WithMaxSearchLength(4, true)
+		_, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, opts)
+		if _, ok := err.(*ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+		if n := len(fake.Queries()); n != 0 {
+			t.Errorf("Expected no queries, got %d", n)
+		}
+	})
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"john doe", nil},
+		{`"john doe"`, []string{"john doe"}},
+		{`"john doe" admin`, []string{"john doe", "admin"}},
+		{`admin "john  doe" x y`, []string{"admin", "john  doe", "x", "y"}},
+		{`"unterminated phrase`, []string{"unterminated phrase"}},
+		{`"" "  "`, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if terms := searchTerms(tt.value); !reflect.DeepEqual(terms, tt.expected) {
+				t.Errorf("searchTerms(%q) = %q, want %q", tt.value, terms, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplySearchQuotedPhrases(t *testing.T) {
+	db := newDryRunDB(t)
+
+	t.Run("Unquoted value is searched as a whole", func(t *testing.T) {
+		stmt := applySearch(db.Model(&TestUser{}), []string{"name", "email"}, "john doe", NewOptions()).
+			Session(&gorm.Session{DryRun: true}).Find(&[]TestUser{}).Statement
+
+		if !strings.Contains(stmt.SQL.String(), "WHERE LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)") {
+			t.Errorf("Unexpected SQL: %s", stmt.SQL.String())
+		}
+		if !reflect.DeepEqual(stmt.Vars, []interface{}{"%john doe%", "%john doe%"}) {
+			t.Errorf("Unexpected vars: %v", stmt.Vars)
+		}
+	})
+
+	t.Run("Quoted phrase and word", func(t *testing.T) {
+		stmt := applySearch(db.Model(&TestUser{}), []string{"name", "email"}, `"john doe" admin`, NewOptions()).
+			Session(&gorm.Session{DryRun: true}).Find(&[]TestUser{}).Statement
+
+		expected := "WHERE (LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)) AND (LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?))"
+		if !strings.Contains(stmt.SQL.String(), expected) {
+			t.Errorf("Expected %q, got %s", expected, stmt.SQL.String())
+		}
+		if !reflect.DeepEqual(stmt.Vars, []interface{}{"%john doe%", "%john doe%", "%admin%", "%admin%"}) {
+			t.Errorf("Unexpected vars: %v", stmt.Vars)
+		}
+	})
+}
+
+func TestParseOperatorValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		op      string
+		operand interface{}
+		ok      bool
+	}{
+		{">100", ">", int64(100), true},
+		{"<50", "<", int64(50), true},
+		{">= 2.5", ">=", 2.5, true},
+		{" <=50", "<=", int64(50), true},
+		{"=3", "=", int64(3), true},
+		{"!=0", "<>", int64(0), true},
+		{"100", "", nil, false},
+		{">abc", "", nil, false},
+		{">", "", nil, false},
+		{">inf", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			op, operand, ok := parseOperatorValue(tt.value)
+			if op != tt.op || operand != tt.operand || ok != tt.ok {
+				t.Errorf("Expected (%q, %v, %v), got (%q, %v, %v)", tt.op, tt.operand, tt.ok, op, operand, ok)
+			}
+		})
+	}
+}
+
+func TestApplyColumnSearches(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   string
+		value    string
+		expected string
+		args     []interface{}
+	}{
+		{"Greater than", "price", ">100", "WHERE price > ?", []interface{}{int64(100)}},
+		{"Less than", "price", "<100", "WHERE price < ?", []interface{}{int64(100)}},
+		{"Greater or equal", "price", ">=1.5", "WHERE price >= ?", []interface{}{1.5}},
+		{"Less or equal", "price", "<=50", "WHERE price <= ?", []interface{}{int64(50)}},
+		{"Equal", "price", "=7", "WHERE price = ?", []interface{}{int64(7)}},
+		{"Not equal", "price", "!=7", "WHERE price <> ?", []interface{}{int64(7)}},
+		{"No operator falls back to LIKE", "price", "100", "WHERE LOWER(price) LIKE LOWER(?)", []interface{}{"%100%"}},
+		{"Operators ignored on other columns", "name", ">100", "WHERE LOWER(name) LIKE LOWER(?)", []interface{}{"%>100%"}},
+		{"Mapped column", "cost", ">5", "WHERE products.price > ?", []interface{}{int64(5)}},
+	}
+
+	opts := NewOptions().
+		WithOperatorColumns("price", "cost").
+		WithSearchableMap(map[string]string{"cost": "products.price"})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+			searches := []dto.ColumnSearch{{Column: tt.column, Value: tt.value}}
+
+			query, applied := applyColumnSearches(db.Model(&TestUser{}), []string{"name", "price"}, searches, opts)
+			if !applied {
+				t.Fatal("Expected the column search to apply")
+			}
+
+			stmt := query.Session(&gorm.Session{DryRun: true}).Find(&[]TestUser{}).Statement
+			if sql := stmt.SQL.String(); !strings.HasSuffix(sql, tt.expected) {
+				t.Errorf("Expected %q in %s", tt.expected, sql)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.args) {
+				t.Errorf("Expected args %v, got %v", tt.args, stmt.Vars)
+			}
+		})
+	}
+
+	t.Run("Unknown columns are ignored", func(t *testing.T) {
+		db := newDryRunDB(t)
+		searches := []dto.ColumnSearch{{Column: "password", Value: "x"}}
+
+		if _, applied := applyColumnSearches(db.Model(&TestUser{}), []string{"name"}, searches, opts); applied {
+			t.Error("Expected no condition for a non-searchable column")
+		}
+	})
+}
+
+func TestOfReturnColumnSearch(t *testing.T) {
+	fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("search[value]=jo&columns[0][data]=id&columns[0][search][value]=%3E%3D3&columns[1][data]=name")
+
+	var users []TestUser
+	opts := NewOptions().WithOperatorColumns("id")
+	if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"id", "name"}, nil, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	last := fake.LastSelect()
+	expected := "SELECT * FROM `test_users` WHERE (LOWER(id) LIKE LOWER(?) OR LOWER(name) LIKE LOWER(?)) AND id >= ? LIMIT ?"
+	if last.SQL != expected {
+		t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+	}
+	if fake.CountQueries() != 2 {
+		t.Errorf("Expected a filtered count, got %d count queries", fake.CountQueries())
+	}
+}
+
+func TestApplySearchUnaccent(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{"postgres", "WHERE LOWER(unaccent(name)) LIKE LOWER(unaccent(?))"},
+		{"mysql", "WHERE LOWER(name) LIKE LOWER(?)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			db := newNamedDryRunDB(t, tt.dialect)
+			opts := NewOptions().WithUnaccentSearch(true)
+
+			sql := dryRunSQL(applySearch(db.Model(&TestUser{}), []string{"name"}, "jose", opts))
+			if !strings.Contains(sql, tt.expected) {
+				t.Errorf("Expected %q in %s", tt.expected, sql)
+			}
+		})
+	}
+}
+
+func TestOfReturnSearchCallback(t *testing.T) {
+	fullText := func(q *gorm.DB, searchable []string, value string) *gorm.DB {
+		return q.Where("MATCH(name, email) AGAINST (?)", value).Or("id = ?", len(searchable))
+	}
+
+	t.Run("Callback replaces the built-in search", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=50%25_off")
+
+		var users []TestUser
+		opts := NewOptions().WithSearchCallback(fullText)
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name", "email"}, nil, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		last := fake.LastSelect()
+		expected := "SELECT * FROM `test_users` WHERE MATCH(name, email) AGAINST (?) OR id = ? LIMIT ?"
+		if last.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+		}
+		if len(last.Args) == 0 || last.Args[0] != "50%_off" {
+			t.Errorf("Expected the raw search value without escaping, got %v", last.Args)
+		}
+		if fake.CountQueries() != 2 {
+			t.Errorf("Expected a filtered count, got %d count queries", fake.CountQueries())
+		}
+	})
+
+	t.Run("Callback is grouped with other filters", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=john&columns[0][data]=id&columns[0][search][value]=3")
+
+		var users []TestUser
+		opts := NewOptions().WithSearchCallback(fullText)
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"id", "name"}, nil, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "SELECT * FROM `test_users` WHERE (MATCH(name, email) AGAINST (?) OR id = ?) AND LOWER(id) LIKE LOWER(?) LIMIT ?"
+		if last := fake.LastSelect(); last.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+		}
+	})
+}
+
+func TestOfReturnSearchFallback(t *testing.T) {
+	orderable := map[string]string{"name": "name", "mail": "email", "alias": "email"}
+
+	t.Run("Searches the orderable columns", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, orderable, NewOptions().WithSearchFallback(true)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "SELECT * FROM `test_users` WHERE LOWER(email) LIKE LOWER(?) OR LOWER(name) LIKE LOWER(?) LIMIT ?"
+		if last := fake.LastSelect(); last.SQL != expected {
+			t.Errorf("Unexpected query:\n got  %s\n want %s", last.SQL, expected)
+		}
+	})
+
+	t.Run("Explicit searchable columns win", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, orderable, NewOptions().WithSearchFallback(true)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if last := fake.LastSelect(); strings.Contains(last.SQL, "email") {
+			t.Errorf("Expected only the searchable columns, got %s", last.SQL)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+		db := newFakeGormDB(t, fake)
+		c, _ := newTestContext("search[value]=jo")
+
+		var users []TestUser
+		if _, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, orderable, NewOptions()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if last := fake.LastSelect(); strings.Contains(last.SQL, "WHERE") {
+			t.Errorf("Expected no search without searchable columns, got %s", last.SQL)
+		}
+	})
+}
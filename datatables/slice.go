@@ -0,0 +1,157 @@
+package datatables
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// OfSlice executes the DataTables server-side logic over an already-loaded
+// Go slice instead of a database query, for small datasets such as
+// configuration tables or enums where a GORM query is overkill.
+//
+// The whole slice is converted and processed in memory on every request, so
+// it is meant for small datasets only (a few thousand rows at most); use
+// OfReturn for anything backed by a table.
+//
+// Columns refer to the output row keys (JSON field names), not database
+// columns:
+//   - search: case-insensitive substring match on the searchable columns
+//     (diacritics are ignored with WithUnaccentSearch)
+//   - ordering: the requested orderable column, compared by value (numbers
+//     and times by value, other values as text); without a valid order,
+//     WithDefaultOrder terms apply, otherwise the slice order is kept
+//   - pagination: start and length, as for OfReturn
+//
+// Every option that acts on rows rather than on SQL applies as for OfReturn,
+// through the same code: row transformations (Add, Edit, Remove, indexes, ...),
+// FetchHook, NormalizeNumbers, FlattenCollections, ColumnACL, RowFilter,
+// MatchHighlight, PagePostProcess, GroupTotals, WithArrayData, KeyCase,
+// FieldsParam, EmptyMessage, ResultLimit, and MaxOffset. Options specific to
+// SQL queries (dialect, computed columns, cursors, counts, footer aggregates,
+// retries, timeouts, and database handles) are ignored.
+//
+// Parameters:
+//   - c: Gin context containing request parameters
+//   - data: The rows to serve; the slice is not modified
+//   - searchable: List of row keys that support global search
+//   - orderable: Mapping between frontend column names and row keys
+//   - opts: Column customizations, as for OfReturn
+//
+// Example:
+//   statuses := []Status{{Code: "A", Label: "Active"}, {Code: "I", Label: "Inactive"}}
+//   result, err := datatables.OfSlice(c, statuses,
+//       []string{"code", "label"},
+//       map[string]string{"code": "code", "label": "label"},
+//       datatables.NewOptions(),
+//   )
+func OfSlice[T any](
+	c *gin.Context,
+	data []T,
+	searchable []string,
+	orderable map[string]string,
+	opts Options,
+) (dto.Datatables, error) {
+	params := parseParams(c, opts)
+
+	if err := validateIndexBase(opts); err != nil {
+		return dto.Datatables{}, err
+	}
+	if opts.DefaultOrder != "" {
+		if err := validateDefaultOrder(opts.DefaultOrder); err != nil {
+			return dto.Datatables{}, err
+		}
+	}
+
+	// Columns hidden by the ACL can be neither searched nor ordered by
+	if opts.ColumnACL != nil {
+		searchable, orderable = aclColumns(aclContext(c), searchable, orderable, opts)
+	}
+
+	// Apply the initial search and length cap
+	var err error
+	if params.Search, err = resolveSearch(params.Search, opts); err != nil {
+		return dto.Datatables{}, err
+	}
+	if params.Search != "" && len(searchable) == 0 {
+		warnf(opts, "search value ignored: no searchable columns are configured")
+	}
+	if params.Start, err = limitOffset(params.Start, opts); err != nil {
+		return dto.Datatables{}, err
+	}
+	requestedLength := params.Length
+	params.Length = limitResult(params.Length, opts)
+
+	// Convert a copy of the slice, so the FetchHook cannot modify the input
+	items := append([]T(nil), data...)
+	rows, err := fetchedRows(&items, opts)
+	if err != nil {
+		return dto.Datatables{}, err
+	}
+	total := int64(len(rows))
+
+	// Search the searchable columns
+	if params.Search != "" && len(searchable) > 0 {
+		rows = filterRows(rows, searchable, params.Search, opts.UnaccentSearch)
+	}
+	filtered := int64(len(rows))
+
+	// Order and paginate
+	sortSliceRows(rows, sliceOrder(params, orderable, opts))
+	rows = paginateRows(rows, params.Start, params.Length)
+
+	return buildResponse(c, rows, params, searchable, opts, pageSummary{
+		total:           total,
+		filtered:        filtered,
+		requestedLength: requestedLength,
+	})
+}
+
+// sliceOrderTerm is a row key and direction rows are ordered by.
+type sliceOrderTerm struct {
+	key  string
+	desc bool
+}
+
+// sliceOrder resolves the ordering of OfSlice: the requested orderable
+// column, or the DefaultOrder terms when no valid order was requested.
+func sliceOrder(params dto.Params, orderable map[string]string, opts Options) []sliceOrderTerm {
+	if params.Order != "" && !opts.OrderingDisabled {
+		if key, ok := orderable[params.Order]; ok {
+			return []sliceOrderTerm{{key: key, desc: normalizeDir(params.Dir) == "desc"}}
+		}
+	}
+
+	var terms []sliceOrderTerm
+	if opts.DefaultOrder != "" {
+		for _, term := range strings.Split(opts.DefaultOrder, ",") {
+			fields := strings.Fields(term)
+			terms = append(terms, sliceOrderTerm{key: fields[0], desc: len(fields) == 2 && strings.EqualFold(fields[1], "desc")})
+		}
+	}
+	return terms
+}
+
+// sortSliceRows stably sorts rows by the order terms, comparing values with
+// compareValues. Rows comparing equal keep their slice order.
+func sortSliceRows(rows []map[string]interface{}, terms []sliceOrderTerm) {
+	if len(terms) == 0 {
+		return
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		for _, term := range terms {
+			cmp := compareValues(rows[a][term.key], rows[b][term.key])
+			if cmp == 0 {
+				continue
+			}
+			if term.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
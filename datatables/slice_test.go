@@ -0,0 +1,192 @@
+package datatables
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testStatus struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+	Rank  int    `json:"rank"`
+}
+
+func TestOfSlice(t *testing.T) {
+	statuses := []testStatus{
+		{Code: "A", Label: "Active", Rank: 10},
+		{Code: "I", Label: "Inactive", Rank: 2},
+		{Code: "P", Label: "Pending", Rank: 7},
+		{Code: "X", Label: "Archived", Rank: 7},
+	}
+	searchable := []string{"label"}
+	orderable := map[string]string{"code": "code", "label": "label", "rank": "rank"}
+
+	codes := func(data interface{}) string {
+		var codes []string
+		for _, row := range data.([]map[string]interface{}) {
+			codes = append(codes, row["code"].(string))
+		}
+		return strings.Join(codes, ",")
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		opts     Options
+		total    int64
+		filtered int64
+		expected string
+	}{
+		{"Slice order by default", "", NewOptions(), 4, 4, "A,I,P,X"},
+		{"Search the searchable columns", "search[value]=ACTIVE", NewOptions(), 4, 2, "A,I"},
+		{"Search ignores other columns", "search[value]=x", NewOptions(), 4, 0, ""},
+		{"Order by text", "order[0][column]=label&order[0][dir]=desc", NewOptions(), 4, 4, "P,I,X,A"},
+		{"Order numbers by value, keeping ties stable", "order[0][column]=rank&order[0][dir]=asc", NewOptions(), 4, 4, "I,P,X,A"},
+		{"Unknown order column keeps the slice order", "order[0][column]=secret", NewOptions(), 4, 4, "A,I,P,X"},
+		{"Default order", "", NewOptions().WithDefaultOrder("rank DESC, code DESC"), 4, 4, "A,X,P,I"},
+		{"Paginate", "start=1&length=2&order[0][column]=code&order[0][dir]=asc", NewOptions(), 4, 4, "I,P"},
+		{"Search, order, and paginate", "search[value]=in&start=1&length=2&order[0][column]=label", NewOptions(), 4, 2, "P"},
+		{"Start past the end", "start=10", NewOptions(), 4, 4, ""},
+		{"Result limit caps all rows", "length=-1", NewOptions().WithResultLimit(2), 4, 4, "A,I"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(tt.query)
+
+			res, err := OfSlice(c, statuses, searchable, orderable, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if res.RecordsTotal != tt.total || res.RecordsFiltered != tt.filtered {
+				t.Errorf("Expected counts %d/%d, got %d/%d", tt.total, tt.filtered, res.RecordsTotal, res.RecordsFiltered)
+			}
+			if got := codes(res.Data); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("Options transform the page", func(t *testing.T) {
+		c, _ := newTestContext("draw=4&start=2&length=1")
+
+		opts := NewOptions().
+			Add("badge", func(row map[string]interface{}) interface{} { return "[" + row["code"].(string) + "]" }).
+			Remove("rank")
+		res, err := OfSlice(c, statuses, searchable, orderable, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []map[string]interface{}{{"code": "P", "label": "Pending", "badge": "[P]", "DT_RowIndex": 3}}
+		if res.Draw != 4 || !reflect.DeepEqual(res.Data, expected) {
+			t.Errorf("Expected draw 4 and %v, got %d and %v", expected, res.Draw, res.Data)
+		}
+	})
+
+	t.Run("Array data", func(t *testing.T) {
+		c, _ := newTestContext("length=2")
+
+		res, err := OfSlice(c, statuses, searchable, orderable, NewOptions().WithArrayData([]string{"code", "rank"}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := [][]interface{}{{"A", 10}, {"I", 2}}
+		if !reflect.DeepEqual(res.Data, expected) {
+			t.Errorf("Expected %v, got %v", expected, res.Data)
+		}
+	})
+
+	t.Run("Empty slice", func(t *testing.T) {
+		c, _ := newTestContext("")
+
+		res, err := OfSlice(c, []testStatus(nil), searchable, orderable, NewOptions())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rows, ok := res.Data.([]map[string]interface{}); !ok || rows == nil || len(rows) != 0 {
+			t.Errorf("Expected an empty, non-nil page, got %#v", res.Data)
+		}
+	})
+
+	t.Run("Input slice is not modified", func(t *testing.T) {
+		c, _ := newTestContext("order[0][column]=code&order[0][dir]=desc")
+
+		if _, err := OfSlice(c, statuses, searchable, orderable, NewOptions()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if statuses[0].Code != "A" || statuses[3].Code != "X" {
+			t.Errorf("Expected the input order to be kept, got %v", statuses)
+		}
+	})
+
+	t.Run("Malformed default order is rejected", func(t *testing.T) {
+		c, _ := newTestContext("")
+
+		_, err := OfSlice(c, statuses, searchable, orderable, NewOptions().WithDefaultOrder("rank sideways"))
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected a ValidationError, got %v", err)
+		}
+	})
+}
+
+func TestOfSliceMatchesOfReturn(t *testing.T) {
+	users := []TestUser{
+		{ID: 1, Name: "John", Email: "john@example.com"},
+		{ID: 2, Name: "Jane", Email: "jane@example.com"},
+		{ID: 3, Name: "Joe", Email: "joe@example.com"},
+	}
+	keepOdd := func(row map[string]interface{}) bool { return row["id"].(int)%2 == 1 }
+
+	tests := []struct {
+		name  string
+		query string
+		users []TestUser
+		opts  Options
+	}{
+		{"Key case", "", users, NewOptions().Add("display_name", func(row map[string]interface{}) interface{} { return row["name"] }).WithKeyCase(CamelCase)},
+		{"Field selection", "fields=id,name", users, NewOptions().WithFieldSelection("")},
+		{"Row filter", "", users, NewOptions().WithRowFilter(keepOdd)},
+		{"Page post-process", "", users, NewOptions().WithPagePostProcess(func(rows []map[string]interface{}) ([]map[string]interface{}, error) {
+			return rows[:1], nil
+		})},
+		{"Empty message", "", nil, NewOptions().WithEmptyMessage("No users yet")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{}
+			fake.handler = func(ctx context.Context, query string, args []interface{}) (fakeResult, error) {
+				if isCountQuery(query) {
+					return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(len(tt.users))}}}, nil
+				}
+				return userResult(tt.users...), nil
+			}
+			db := newFakeGormDB(t, fake)
+
+			c, _ := newTestContext(tt.query)
+			var dest []TestUser
+			expected, err := OfReturn(c, db.Model(&TestUser{}), &dest, nil, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected OfReturn error: %v", err)
+			}
+
+			c, _ = newTestContext(tt.query)
+			res, err := OfSlice(c, tt.users, nil, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected OfSlice error: %v", err)
+			}
+
+			if !reflect.DeepEqual(res, expected) {
+				t.Errorf("Expected the OfReturn response\n %+v\ngot\n %+v", expected, res)
+			}
+		})
+	}
+}
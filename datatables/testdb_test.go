@@ -0,0 +1,201 @@
+package datatables
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+// fakeResult is a scripted result set returned by fakeDB.
+type fakeResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// fakeQuery records a single statement executed against fakeDB.
+type fakeQuery struct {
+	SQL  string
+	Args []interface{}
+}
+
+// fakeDB is a minimal database/sql driver used to exercise OfReturn end-to-end
+// without a real database. Every statement is recorded, and results are
+// produced by the handler (or by the count/rows defaults when it is nil).
+type fakeDB struct {
+	mu      sync.Mutex
+	queries []fakeQuery
+
+	// count is returned for COUNT(*) queries when handler is nil
+	count int64
+
+	// result is returned for every other query when handler is nil
+	result fakeResult
+
+	// handler, when set, produces the result for every query
+	handler func(ctx context.Context, query string, args []interface{}) (fakeResult, error)
+}
+
+// newFakeGormDB opens a GORM connection backed by a fakeDB.
+func newFakeGormDB(t *testing.T, fake *fakeDB) *gorm.DB {
+	t.Helper()
+
+	sqlDB := sql.OpenDB(fakeConnector{fake: fake})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{ConnPool: sqlDB})
+	if err != nil {
+		t.Fatalf("failed to open fake database: %v", err)
+	}
+	return db
+}
+
+// Queries returns a copy of the recorded statements.
+func (f *fakeDB) Queries() []fakeQuery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeQuery(nil), f.queries...)
+}
+
+// CountQueries returns the number of recorded COUNT queries.
+func (f *fakeDB) CountQueries() int {
+	n := 0
+	for _, q := range f.Queries() {
+		if isCountQuery(q.SQL) {
+			n++
+		}
+	}
+	return n
+}
+
+// LastSelect returns the last recorded non-COUNT query.
+func (f *fakeDB) LastSelect() fakeQuery {
+	queries := f.Queries()
+	for i := len(queries) - 1; i >= 0; i-- {
+		if !isCountQuery(queries[i].SQL) {
+			return queries[i]
+		}
+	}
+	return fakeQuery{}
+}
+
+func (f *fakeDB) query(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	plainArgs := make([]interface{}, len(args))
+	for i, a := range args {
+		plainArgs[i] = a.Value
+	}
+
+	f.mu.Lock()
+	f.queries = append(f.queries, fakeQuery{SQL: query, Args: plainArgs})
+	handler := f.handler
+	f.mu.Unlock()
+
+	var res fakeResult
+	switch {
+	case handler != nil:
+		var err error
+		if res, err = handler(ctx, query, plainArgs); err != nil {
+			return nil, err
+		}
+	case isCountQuery(query):
+		res = fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{f.count}}}
+	default:
+		res = f.result
+	}
+
+	return &fakeRows{result: res}, nil
+}
+
+// isCountQuery reports whether the statement is a top-level COUNT query.
+func isCountQuery(query string) bool {
+	return strings.HasPrefix(strings.ToLower(query), "select count(")
+}
+
+type fakeConnector struct {
+	fake *fakeDB
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{fake: c.fake}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver: use the connector")
+}
+
+type fakeConn struct {
+	fake *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver: prepared statements not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fake driver: transactions not supported")
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.fake.query(ctx, query, args)
+}
+
+type fakeRows struct {
+	result fakeResult
+	pos    int
+}
+
+func (r *fakeRows) Columns() []string { return r.result.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+// newTestContext builds a Gin context for a GET request with the given raw query string.
+func newTestContext(rawQuery string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil)
+	return c, w
+}
+
+// userResult builds a fakeResult for TestUser rows.
+func userResult(users ...TestUser) fakeResult {
+	res := fakeResult{columns: []string{"id", "name", "email"}}
+	for _, u := range users {
+		res.rows = append(res.rows, []driver.Value{int64(u.ID), u.Name, u.Email})
+	}
+	return res
+}
+
+// dryRunSQL renders the SELECT statement a query would execute, using a dry-run session.
+func dryRunSQL(query *gorm.DB) string {
+	var users []TestUser
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&users).Statement
+	return stmt.SQL.String()
+}
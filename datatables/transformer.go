@@ -1,65 +1,480 @@
 package datatables
 
+import (
+	"database/sql/driver"
+	"fmt"
+	"html"
+	"html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stage is a step of the row transformation pipeline run by applyOptions.
+// See WithTransformOrder for reordering the stages.
+type Stage int
+
+const (
+	// StageIndex adds the index column (DT_RowIndex) and row identifier (DT_RowId)
+	StageIndex Stage = iota
+	// StageAdd adds custom columns (Add, AddWithValues, WithDeletedFlag)
+	StageAdd
+	// StageEdit edits existing columns (Edit, EditWithValues, WithValueMapper, WithOrthogonal)
+	StageEdit
+	// StageTrim trims and escapes string values (WithTrimStrings, WithHTMLEscape)
+	StageTrim
+	// StageAlias renames columns (WithColumnAlias)
+	StageAlias
+	// StageRemove removes columns (SetGlobalRemove, Remove, WithSearchableHidden, WithColumnACL, WithOmitEmpty)
+	StageRemove
+)
+
+// defaultTransformOrder is the pipeline order used when Options.TransformOrder is empty
+var defaultTransformOrder = []Stage{StageIndex, StageAdd, StageEdit, StageTrim, StageAlias, StageRemove}
+
+// transformOrder returns the stages to run: the custom order with unknown and
+// duplicate stages dropped, followed by any omitted stages in default order.
+func transformOrder(order []Stage) []Stage {
+	if len(order) == 0 {
+		return defaultTransformOrder
+	}
+
+	stages := make([]Stage, 0, len(defaultTransformOrder))
+	seen := make(map[Stage]bool, len(defaultTransformOrder))
+	for _, stage := range append(append([]Stage(nil), order...), defaultTransformOrder...) {
+		if stage < StageIndex || stage > StageRemove || seen[stage] {
+			continue
+		}
+		seen[stage] = true
+		stages = append(stages, stage)
+	}
+	return stages
+}
+
 // applyOptions processes DataTables customization options such as adding new columns,
 // editing existing ones, removing unwanted fields, and setting row indexes.
 //
-// The transformation is applied in the following order:
+// By default the transformation is applied in the following order:
 //  1. Copy original row data
-//  2. Add index column (DT_RowIndex)
+//  2. Add index column (DT_RowIndex) and row identifier (DT_RowId)
 //  3. Add custom columns (from Options.AddColumns)
 //  4. Edit existing columns (from Options.EditColumns)
-//  5. Remove unwanted columns (from Options.RemoveColumns)
+//  5. Trim string values and rename aliased columns
+//  6. Remove unwanted columns (from SetGlobalRemove, Options.RemoveColumns and Options.ColumnACL)
+//
+// Options.TransformOrder reorders steps 2 to 6. With a custom order, Add and
+// Edit callbacks receive the row as transformed by the earlier stages instead
+// of the original row.
 //
 // Parameters:
+//   - c: The request, passed to Options.ColumnACL (nil for OfParams, see aclContext)
 //   - data: Slice of maps representing rows
 //   - opts: Options struct containing transformation rules
 //   - start: Starting offset for index calculation (used when ResetIndex is false)
 //
 // Returns the transformed data with all options applied.
-func applyOptions(data []map[string]interface{}, opts Options, start int) []map[string]interface{} {
+func applyOptions(c *gin.Context, data []map[string]interface{}, opts Options, start int) []map[string]interface{} {
 	if data == nil {
 		return nil
 	}
 
 	out := make([]map[string]interface{}, 0, len(data))
 
+	// A negative offset would produce zero or negative indexes
+	if start < 0 {
+		start = 0
+	}
+
+	// Merge package-level removes with the per-Options list
+	removeColumns := append(append(globalRemoveColumns(), opts.RemoveColumns...), opts.SearchableHidden...)
+
+	base := opts.indexBase()
+	stages := transformOrder(opts.TransformOrder)
+
+	// ColumnACL decisions, evaluated once per column
+	visible := make(map[string]bool)
+	aclCtx := aclContext(c)
+	customOrder := len(opts.TransformOrder) > 0
+
 	for i, row := range data {
 		// Create a new map to avoid modifying the original
-		newRow := make(map[string]interface{})
-		for k, v := range row {
-			newRow[k] = v
+		newRow := copyRow(row)
+
+		// With a custom order, callbacks see the results of earlier stages
+		input := func() map[string]interface{} {
+			if customOrder {
+				return copyRow(newRow)
+			}
+			return row
 		}
 
-		// Step 1: Add index column
-		if opts.IndexColumn != "" {
-			if opts.ResetIndex {
-				// Index starts from 1 on each page
-				newRow[opts.IndexColumn] = i + 1
-			} else {
-				// Index continues from previous pages
-				newRow[opts.IndexColumn] = start + i + 1
+		for _, stage := range stages {
+			switch stage {
+			case StageIndex:
+				// Add index column
+				if opts.IndexColumn != "" {
+					if opts.ResetIndex {
+						// Index starts from the base (1 by default) on each page
+						newRow[opts.IndexColumn] = i + base
+					} else {
+						// Index continues from previous pages
+						newRow[opts.IndexColumn] = start + i + base
+					}
+				}
+
+				// Add the DOM row identifier
+				if id := rowId(row, opts); id != "" {
+					newRow[rowIdKey] = id
+				}
+
+			case StageAdd:
+				// Add custom columns
+				current := input()
+				for colName, fn := range opts.AddColumns {
+					newRow[colName] = fn(current)
+				}
+				for colName, fn := range opts.AddValueColumns {
+					newRow[colName] = fn(current, opts.ContextValues)
+				}
+
+				// Flag soft-deleted rows
+				if opts.DeletedFlagColumn != "" {
+					newRow[opts.DeletedFlagColumn] = isSetValue(current[opts.DeletedAtColumn])
+				}
+
+			case StageEdit:
+				// Edit existing columns
+				current := input()
+				for colName, fn := range opts.EditColumns {
+					if val, ok := newRow[colName]; ok {
+						edited := fn(val, current)
+						if containsString(opts.PreserveNumericColumns, colName) {
+							edited = preserveNumeric(val, edited)
+						}
+						newRow[colName] = edited
+					}
+				}
+				for colName, fn := range opts.EditValueColumns {
+					if val, ok := newRow[colName]; ok {
+						edited := fn(val, current, opts.ContextValues)
+						if containsString(opts.PreserveNumericColumns, colName) {
+							edited = preserveNumeric(val, edited)
+						}
+						newRow[colName] = edited
+					}
+				}
+
+				// Map every cell value
+				if opts.ValueMapper != nil {
+					for colName, val := range newRow {
+						if strings.HasPrefix(colName, "DT_") || colName == opts.IndexColumn {
+							continue
+						}
+						newRow[colName] = opts.ValueMapper(colName, val)
+					}
+				}
+
+				// Split orthogonal columns into display and sort values
+				for colName, orthogonal := range opts.OrthogonalColumns {
+					if val, ok := newRow[colName]; ok {
+						newRow[colName] = orthogonal.cell(val, current)
+					}
+				}
+
+			case StageTrim:
+				// Trim whitespace from string values
+				if opts.TrimStrings {
+					for colName, val := range newRow {
+						if str, ok := val.(string); ok {
+							newRow[colName] = strings.TrimSpace(str)
+						}
+					}
+				}
+
+				// Escape markup in string values
+				if opts.HTMLEscapeAll || len(opts.HTMLEscapeColumns) > 0 {
+					escapeHTML(newRow, opts)
+				}
+
+			case StageAlias:
+				// Rename aliased columns
+				if len(opts.ColumnAliases) > 0 {
+					renameColumns(newRow, opts.ColumnAliases)
+				}
+
+			case StageRemove:
+				// Remove unwanted columns
+				for _, col := range removeColumns {
+					delete(newRow, col)
+				}
+
+				// Remove the columns the requesting user may not see
+				if opts.ColumnACL != nil {
+					for col := range newRow {
+						if strings.HasPrefix(col, "DT_") || col == opts.IndexColumn {
+							continue
+						}
+						allowed, ok := visible[col]
+						if !ok {
+							allowed = opts.ColumnACL(aclCtx, col)
+							visible[col] = allowed
+						}
+						if !allowed {
+							delete(newRow, col)
+						}
+					}
+				}
+
+				// Drop zero-value cells
+				if opts.OmitEmpty {
+					for col, val := range newRow {
+						if isZeroValue(val) && !strings.HasPrefix(col, "DT_") && col != opts.IndexColumn {
+							delete(newRow, col)
+						}
+					}
+				}
 			}
 		}
 
-		// Step 2: Add custom columns
-		for colName, fn := range opts.AddColumns {
-			newRow[colName] = fn(row)
+		out = append(out, newRow)
+	}
+
+	return out
+}
+
+// OrthogonalColumn formats a cell as DataTables orthogonal data (see WithOrthogonal).
+type OrthogonalColumn struct {
+	Display func(value interface{}, row map[string]interface{}) interface{} // Produces the "display" value
+	Sort    func(value interface{}, row map[string]interface{}) interface{} // Produces the "sort" value (nil keeps the raw value)
+}
+
+// cell builds the {"display": ..., "sort": ...} object for a cell value.
+func (o OrthogonalColumn) cell(value interface{}, row map[string]interface{}) map[string]interface{} {
+	display, sort := value, value
+	if o.Display != nil {
+		display = o.Display(value, row)
+	}
+	if o.Sort != nil {
+		sort = o.Sort(value, row)
+	}
+	return map[string]interface{}{"display": display, "sort": sort}
+}
+
+// isZeroValue reports whether v is nil or the zero value of its type
+// (such as "", 0, false, or a nil pointer).
+func isZeroValue(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// copyRow returns a shallow copy of a row.
+func copyRow(row map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		out[k] = v
+	}
+	return out
+}
+
+// selectFields trims rows to the comma-separated fields (e.g. "name,email"),
+// always keeping the DT_* columns and the index column. Fields missing from the
+// rows are ignored; if none of the requested fields exist, or no field is
+// requested, the rows are left unchanged.
+//
+// Rows are modified in place.
+func selectFields(rows []map[string]interface{}, fields string, indexColumn string) {
+	requested := make(map[string]bool)
+	for _, field := range strings.Split(fields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			requested[field] = true
+		}
+	}
+
+	known := false
+	for _, row := range rows {
+		for key := range row {
+			if requested[key] {
+				known = true
+				break
+			}
 		}
+	}
+	if !known {
+		return
+	}
 
-		// Step 3: Edit existing columns
-		for colName, fn := range opts.EditColumns {
-			if val, ok := newRow[colName]; ok {
-				newRow[colName] = fn(val, row)
+	for _, row := range rows {
+		for key := range row {
+			if !requested[key] && !strings.HasPrefix(key, "DT_") && key != indexColumn {
+				delete(row, key)
 			}
 		}
+	}
+}
 
-		// Step 4: Remove unwanted columns
-		for _, col := range opts.RemoveColumns {
-			delete(newRow, col)
+// renameColumns renames the row keys listed in aliases (from -> to), all at
+// once so that chained aliases do not cascade. Existing target keys are overwritten.
+func renameColumns(row map[string]interface{}, aliases map[string]string) {
+	moved := make(map[string]interface{}, len(aliases))
+	for from, to := range aliases {
+		if val, ok := row[from]; ok {
+			moved[to] = val
 		}
+	}
+	for from := range aliases {
+		delete(row, from)
+	}
+	for to, val := range moved {
+		row[to] = val
+	}
+}
 
-		out = append(out, newRow)
+// preserveNumeric keeps a numeric column numeric after an Edit callback.
+// If the original value is numeric and the edited value is a string, the string
+// is parsed back into an int64 or float64. Non-numeric strings are discarded
+// in favor of the original value. Non-numeric originals are returned as edited.
+func preserveNumeric(original, edited interface{}) interface{} {
+	if !isNumeric(original) {
+		return edited
+	}
+
+	s, ok := edited.(string)
+	if !ok {
+		return edited
+	}
+
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+
+	return original
+}
+
+// isNumeric reports whether the value is of an integer or floating point kind.
+func isNumeric(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// rowIdKey is the row key DataTables uses as the DOM id of a table row
+const rowIdKey = "DT_RowId"
+
+// rowId computes the DT_RowId for a row from opts.RowIdFunc or opts.RowIdColumn.
+// Returns an empty string when no identifier is configured or available.
+func rowId(row map[string]interface{}, opts Options) string {
+	if opts.RowIdFunc != nil {
+		return opts.RowIdFunc(row)
+	}
+
+	if opts.RowIdColumn != "" {
+		if val, ok := row[opts.RowIdColumn]; ok && val != nil {
+			return fmt.Sprint(val)
+		}
+	}
+
+	return ""
+}
+
+// projectRows converts each row map into a slice ordered by columns,
+// matching DataTables' array data source (columns[].data as integer indexes).
+// Columns missing from a row produce nil entries.
+func projectRows(rows []map[string]interface{}, columns []string) [][]interface{} {
+	out := make([][]interface{}, 0, len(rows))
+
+	for _, row := range rows {
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = row[col]
+		}
+		out = append(out, values)
 	}
 
 	return out
 }
+
+// clientArrayColumns reorders the array data columns into the client's
+// current column order (e.g. after the ColReorder extension moved columns):
+// output position i holds the configured column at index columns[i][data].
+// The configured order is kept unless every client column is an integer
+// index into columns.
+func clientArrayColumns(columns []string, clientColumns []string) []string {
+	if len(clientColumns) == 0 {
+		return columns
+	}
+
+	ordered := make([]string, len(clientColumns))
+	for i, data := range clientColumns {
+		idx, err := strconv.Atoi(data)
+		if err != nil || idx < 0 || idx >= len(columns) {
+			return columns
+		}
+		ordered[i] = columns[idx]
+	}
+
+	return ordered
+}
+
+// escapeHTML HTML-escapes the string values of a row's escaped columns
+// (see WithHTMLEscape). Raw columns, template.HTML values, and internal DT_*
+// keys are left unchanged.
+func escapeHTML(row map[string]interface{}, opts Options) {
+	for colName, val := range row {
+		if strings.HasPrefix(colName, "DT_") || containsString(opts.RawHTMLColumns, colName) {
+			continue
+		}
+		if !opts.HTMLEscapeAll && !containsString(opts.HTMLEscapeColumns, colName) {
+			continue
+		}
+		if _, ok := val.(template.HTML); ok {
+			continue
+		}
+		if str, ok := val.(string); ok {
+			row[colName] = html.EscapeString(str)
+		}
+	}
+}
+
+// isSetValue reports whether a nullable value such as a soft-delete timestamp
+// is set: nil, nil pointers, zero times, and driver.Valuer values (such as
+// gorm.DeletedAt or sql.NullTime) that are NULL are not set.
+func isSetValue(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return false
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil || v == nil {
+			return false
+		}
+		value = v
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return !v.IsZero()
+	case *time.Time:
+		return !v.IsZero()
+	case string:
+		return v != ""
+	}
+	return true
+}
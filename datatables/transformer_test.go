@@ -1,8 +1,20 @@
 package datatables
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/bonarizki-dat/Datatables-Gin/datatables/dto"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func TestApplyOptions(t *testing.T) {
@@ -13,7 +25,7 @@ func TestApplyOptions(t *testing.T) {
 		}
 
 		opts := NewOptions().WithIndex("DT_RowIndex", false)
-		result := applyOptions(data, opts, 10) // Start from page offset 10
+		result := applyOptions(nil, data, opts, 10) // Start from page offset 10
 
 		if result[0]["DT_RowIndex"] != 11 {
 			t.Errorf("Expected DT_RowIndex=11, got %v", result[0]["DT_RowIndex"])
@@ -30,7 +42,7 @@ func TestApplyOptions(t *testing.T) {
 		}
 
 		opts := NewOptions().WithIndex("row_num", true)
-		result := applyOptions(data, opts, 50) // Start offset ignored when reset=true
+		result := applyOptions(nil, data, opts, 50) // Start offset ignored when reset=true
 
 		if result[0]["row_num"] != 1 {
 			t.Errorf("Expected row_num=1, got %v", result[0]["row_num"])
@@ -50,7 +62,7 @@ func TestApplyOptions(t *testing.T) {
 			return row["first_name"].(string) + " " + row["last_name"].(string)
 		})
 
-		result := applyOptions(data, opts, 0)
+		result := applyOptions(nil, data, opts, 0)
 
 		if result[0]["full_name"] != "John Doe" {
 			t.Errorf("Expected full_name='John Doe', got %v", result[0]["full_name"])
@@ -70,7 +82,7 @@ func TestApplyOptions(t *testing.T) {
 			return strings.ToLower(value.(string))
 		})
 
-		result := applyOptions(data, opts, 0)
+		result := applyOptions(nil, data, opts, 0)
 
 		if result[0]["email"] != "john@example.com" {
 			t.Errorf("Expected email='john@example.com', got %v", result[0]["email"])
@@ -86,7 +98,7 @@ func TestApplyOptions(t *testing.T) {
 		}
 
 		opts := NewOptions().Remove("password", "internal_id")
-		result := applyOptions(data, opts, 0)
+		result := applyOptions(nil, data, opts, 0)
 
 		if _, exists := result[0]["password"]; exists {
 			t.Error("password should be removed")
@@ -114,7 +126,7 @@ func TestApplyOptions(t *testing.T) {
 			}).
 			Remove("password")
 
-		result := applyOptions(data, opts, 0)
+		result := applyOptions(nil, data, opts, 0)
 
 		// Check index
 		if result[0]["row_num"] != 1 {
@@ -139,7 +151,7 @@ func TestApplyOptions(t *testing.T) {
 
 	t.Run("Nil data", func(t *testing.T) {
 		opts := NewOptions()
-		result := applyOptions(nil, opts, 0)
+		result := applyOptions(nil, nil, opts, 0)
 
 		if result != nil {
 			t.Error("Expected nil result for nil input")
@@ -149,10 +161,807 @@ func TestApplyOptions(t *testing.T) {
 	t.Run("Empty data", func(t *testing.T) {
 		data := []map[string]interface{}{}
 		opts := NewOptions()
-		result := applyOptions(data, opts, 0)
+		result := applyOptions(nil, data, opts, 0)
 
 		if len(result) != 0 {
 			t.Errorf("Expected empty result, got %d items", len(result))
 		}
 	})
 }
+
+func TestApplyOptionsPreserveNumeric(t *testing.T) {
+	data := []map[string]interface{}{
+		{"price": 10.5, "quantity": 3, "name": "Pen"},
+	}
+
+	opts := NewOptions().
+		Edit("price", func(value interface{}, row map[string]interface{}) interface{} {
+			return fmt.Sprintf("%.2f", value)
+		}).
+		Edit("quantity", func(value interface{}, row map[string]interface{}) interface{} {
+			return fmt.Sprintf("%d pcs", value)
+		}).
+		Edit("name", func(value interface{}, row map[string]interface{}) interface{} {
+			return strings.ToUpper(value.(string))
+		}).
+		WithPreserveNumeric("price", "quantity", "name")
+
+	result := applyOptions(nil, data, opts, 0)
+
+	if result[0]["price"] != 10.5 {
+		t.Errorf("Expected price to be coerced back to 10.5, got %v (%T)", result[0]["price"], result[0]["price"])
+	}
+	if result[0]["quantity"] != 3 {
+		t.Errorf("Expected non-numeric edit to be discarded, got %v (%T)", result[0]["quantity"], result[0]["quantity"])
+	}
+	if result[0]["name"] != "PEN" {
+		t.Errorf("Expected non-numeric column to be edited, got %v", result[0]["name"])
+	}
+}
+
+func TestPreserveNumeric(t *testing.T) {
+	tests := []struct {
+		name     string
+		original interface{}
+		edited   interface{}
+		expected interface{}
+	}{
+		{"Integer string", 5, "42", int64(42)},
+		{"Float string", 1.5, " 2.75 ", 2.75},
+		{"Non-numeric string keeps original", uint(7), "seven", uint(7)},
+		{"Numeric edit is kept", 5, 6, 6},
+		{"Non-numeric original is untouched", "abc", "def", "def"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := preserveNumeric(tt.original, tt.edited)
+			if result != tt.expected {
+				t.Errorf("preserveNumeric(%v, %v) = %v (%T), want %v (%T)", tt.original, tt.edited, result, result, tt.expected, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyOptionsGlobalRemove(t *testing.T) {
+	SetGlobalRemove("password", "deleted_at")
+	defer SetGlobalRemove()
+
+	data := []map[string]interface{}{
+		{"id": 1, "name": "John", "password": "secret", "deleted_at": nil, "internal_id": 7},
+	}
+
+	result := applyOptions(nil, data, NewOptions().Remove("internal_id"), 0)
+
+	for _, col := range []string{"password", "deleted_at", "internal_id"} {
+		if _, exists := result[0][col]; exists {
+			t.Errorf("%s should be removed", col)
+		}
+	}
+	if _, exists := result[0]["name"]; !exists {
+		t.Error("name should still exist")
+	}
+
+	opts := NewOptions().Remove("internal_id")
+	if len(opts.RemoveColumns) != 1 {
+		t.Errorf("Global removes should not be copied into Options, got %v", opts.RemoveColumns)
+	}
+}
+
+func TestApplyOptionsRowId(t *testing.T) {
+	data := []map[string]interface{}{
+		{"id": 7, "order_id": 10, "line_id": 2},
+		{"id": nil, "order_id": 11, "line_id": 0},
+	}
+
+	t.Run("Single column", func(t *testing.T) {
+		result := applyOptions(nil, data, NewOptions().WithRowId("id"), 0)
+
+		if result[0]["DT_RowId"] != "7" {
+			t.Errorf("Expected DT_RowId='7', got %#v", result[0]["DT_RowId"])
+		}
+		if _, exists := result[1]["DT_RowId"]; exists {
+			t.Error("DT_RowId should be omitted for nil values")
+		}
+	})
+
+	t.Run("Compound key function", func(t *testing.T) {
+		opts := NewOptions().
+			WithRowId("id").
+			WithRowIdFunc(func(row map[string]interface{}) string {
+				if row["line_id"] == 0 {
+					return ""
+				}
+				return fmt.Sprintf("order-%v-%v", row["order_id"], row["line_id"])
+			})
+
+		result := applyOptions(nil, data, opts, 0)
+
+		if result[0]["DT_RowId"] != "order-10-2" {
+			t.Errorf("Expected DT_RowId='order-10-2', got %#v", result[0]["DT_RowId"])
+		}
+		if _, exists := result[1]["DT_RowId"]; exists {
+			t.Error("DT_RowId should be omitted for empty results")
+		}
+	})
+}
+
+func TestProjectRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "John", "email": "john@example.com"},
+		{"id": 2, "name": "Jane"},
+	}
+
+	result := projectRows(rows, []string{"email", "id", "name"})
+
+	expected := [][]interface{}{
+		{"john@example.com", 1, "John"},
+		{nil, 2, "Jane"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestOfReturnArrayData(t *testing.T) {
+	fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"})}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("")
+
+	var users []TestUser
+	result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithArrayData([]string{"DT_RowIndex", "name", "missing"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, ok := result.Data.([][]interface{})
+	if !ok {
+		t.Fatalf("Expected [][]interface{}, got %T", result.Data)
+	}
+	if !reflect.DeepEqual(data, [][]interface{}{{1, "John", nil}}) {
+		t.Errorf("Unexpected array data: %v", data)
+	}
+}
+
+func TestOfReturnArrayDataColumnOrder(t *testing.T) {
+	result := userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"})
+	columns := []string{"id", "name", "email"}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []interface{}
+	}{
+		{"Configured order without columns", "", []interface{}{1, "John", "john@example.com"}},
+		{"Reordered columns", "columns[0][data]=2&columns[1][data]=0&columns[2][data]=1", []interface{}{"john@example.com", 1, "John"}},
+		{"Subset of columns", "columns[0][data]=1&columns[1][data]=2", []interface{}{"John", "john@example.com"}},
+		{"Named columns keep the configured order", "columns[0][data]=email&columns[1][data]=id", []interface{}{1, "John", "john@example.com"}},
+		{"Out of range index keeps the configured order", "columns[0][data]=3&columns[1][data]=0", []interface{}{1, "John", "john@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeGormDB(t, &fakeDB{count: 1, result: result})
+			c, _ := newTestContext(tt.query)
+
+			var users []TestUser
+			res, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, NewOptions().WithArrayData(columns))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			data := res.Data.([][]interface{})
+			if !reflect.DeepEqual(data, [][]interface{}{tt.expected}) {
+				t.Errorf("Expected %v, got %v", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestApplyOptionsIndexBoundaries(t *testing.T) {
+	makeRows := func(n int) []map[string]interface{} {
+		rows := make([]map[string]interface{}, n)
+		for i := range rows {
+			rows[i] = map[string]interface{}{"id": i}
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name        string
+		rows        int
+		start       int
+		first, last int
+	}{
+		{"Page boundary start=500 length=100", 100, 500, 501, 600},
+		{"Second page start=100", 100, 100, 101, 200},
+		{"All records length=-1", 250, 0, 1, 250},
+		{"Negative start is clamped", 3, -20, 1, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := applyOptions(nil, makeRows(tt.rows), NewOptions(), tt.start)
+
+			if result[0]["DT_RowIndex"] != tt.first {
+				t.Errorf("Expected first index %d, got %v", tt.first, result[0]["DT_RowIndex"])
+			}
+			if result[len(result)-1]["DT_RowIndex"] != tt.last {
+				t.Errorf("Expected last index %d, got %v", tt.last, result[len(result)-1]["DT_RowIndex"])
+			}
+		})
+	}
+}
+
+func TestApplyOptionsIndexBase(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1}, {"id": 2}, {"id": 3}}
+
+	tests := []struct {
+		name  string
+		opts  Options
+		first int
+	}{
+		{"Base 0", NewOptions().WithIndexBase(0), 20},
+		{"Base 0 with reset", NewOptions().WithIndexBase(0).WithIndex("DT_RowIndex", true), 0},
+		{"Base 1", NewOptions().WithIndexBase(1), 21},
+		{"Base 1 with reset", NewOptions().WithIndexBase(1).WithIndex("DT_RowIndex", true), 1},
+		{"Default base with reset", NewOptions().WithIndex("DT_RowIndex", true), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := applyOptions(nil, rows, tt.opts, 20)
+
+			for i, row := range result {
+				if row["DT_RowIndex"] != tt.first+i {
+					t.Errorf("Row %d: expected index %d, got %v", i, tt.first+i, row["DT_RowIndex"])
+				}
+			}
+		})
+	}
+}
+
+func TestOfReturnNegativeIndexBase(t *testing.T) {
+	fake := &fakeDB{count: 1}
+	db := newFakeGormDB(t, fake)
+	c, _ := newTestContext("draw=1&start=0&length=10")
+
+	var users []TestUser
+	_, err := OfReturn(c, db.Model(&TestUser{}), &users, []string{"name"}, nil, NewOptions().WithIndexBase(-1))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "IndexBase" {
+		t.Fatalf("Expected an IndexBase ValidationError, got %v", err)
+	}
+	if len(fake.Queries()) != 0 {
+		t.Errorf("Expected no queries for invalid options, got %v", fake.Queries())
+	}
+}
+
+func TestApplyOptionsContextValues(t *testing.T) {
+	data := []map[string]interface{}{
+		{"price": 10, "email": "john@example.com"},
+	}
+
+	base := NewOptions().
+		Add("plain", func(row map[string]interface{}) interface{} {
+			return "unchanged"
+		}).
+		AddWithValues("price_label", func(row, values map[string]interface{}) interface{} {
+			return fmt.Sprintf("%v %v", row["price"], values["currency"])
+		}).
+		EditWithValues("email", func(value interface{}, row, values map[string]interface{}) interface{} {
+			if values["mask"] == true {
+				return "***"
+			}
+			return value
+		})
+
+	t.Run("Callbacks receive context values", func(t *testing.T) {
+		opts := base.WithContextValue("currency", "EUR").WithContextValue("mask", true)
+		result := applyOptions(nil, data, opts, 0)
+
+		if result[0]["price_label"] != "10 EUR" {
+			t.Errorf("Expected price_label='10 EUR', got %v", result[0]["price_label"])
+		}
+		if result[0]["email"] != "***" {
+			t.Errorf("Expected masked email, got %v", result[0]["email"])
+		}
+		if result[0]["plain"] != "unchanged" {
+			t.Errorf("Expected Add callback to keep working, got %v", result[0]["plain"])
+		}
+	})
+
+	t.Run("Values are scoped to each Options copy", func(t *testing.T) {
+		_ = base.WithContextValue("currency", "EUR")
+		result := applyOptions(nil, data, base.WithContextValue("currency", "USD"), 0)
+
+		if result[0]["price_label"] != "10 USD" {
+			t.Errorf("Expected price_label='10 USD', got %v", result[0]["price_label"])
+		}
+		if result[0]["email"] != "john@example.com" {
+			t.Errorf("Expected unmasked email, got %v", result[0]["email"])
+		}
+	})
+}
+
+func TestApplyOptionsTrimStrings(t *testing.T) {
+	data := []map[string]interface{}{
+		{"name": "  John Doe \t", "code": "A1 ", "age": 30, "note": nil},
+	}
+
+	t.Run("Trims string values only", func(t *testing.T) {
+		opts := NewOptions().WithTrimStrings(true).
+			Edit("code", func(value interface{}, row map[string]interface{}) interface{} {
+				return "[" + value.(string) + "]  "
+			})
+		result := applyOptions(nil, data, opts, 0)
+
+		if result[0]["name"] != "John Doe" {
+			t.Errorf("Expected name='John Doe', got %q", result[0]["name"])
+		}
+		if result[0]["code"] != "[A1 ]" {
+			t.Errorf("Expected edited value to be trimmed, got %q", result[0]["code"])
+		}
+		if result[0]["age"] != 30 || result[0]["note"] != nil {
+			t.Errorf("Expected non-string values untouched, got %v", result[0])
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		result := applyOptions(nil, data, NewOptions(), 0)
+
+		if result[0]["name"] != "  John Doe \t" {
+			t.Errorf("Expected name untouched, got %q", result[0]["name"])
+		}
+	})
+}
+
+func TestApplyOptionsColumnAlias(t *testing.T) {
+	t.Run("Renames after Edit and before Remove", func(t *testing.T) {
+		data := []map[string]interface{}{
+			{"email_address": "JOHN@EXAMPLE.COM", "secret_code": "x", "name": "John"},
+		}
+
+		opts := NewOptions().
+			WithColumnAlias("email_address", "email").
+			WithColumnAlias("secret_code", "code").
+			Edit("email_address", func(value interface{}, row map[string]interface{}) interface{} {
+				return strings.ToLower(value.(string))
+			}).
+			Remove("code")
+		result := applyOptions(nil, data, opts, 0)
+
+		if result[0]["email"] != "john@example.com" {
+			t.Errorf("Expected edited value under alias, got %v", result[0])
+		}
+		if _, ok := result[0]["email_address"]; ok {
+			t.Errorf("Expected original key to be gone, got %v", result[0])
+		}
+		if _, ok := result[0]["code"]; ok {
+			t.Errorf("Expected Remove to apply to the alias, got %v", result[0])
+		}
+	})
+
+	t.Run("Overwrites existing targets without cascading", func(t *testing.T) {
+		data := []map[string]interface{}{
+			{"a": 1, "b": 2, "c": 3},
+		}
+
+		opts := NewOptions().WithColumnAlias("a", "b").WithColumnAlias("b", "c")
+		result := applyOptions(nil, data, opts, 0)
+
+		expected := map[string]interface{}{"b": 1, "c": 2, "DT_RowIndex": 1}
+		if !reflect.DeepEqual(result[0], expected) {
+			t.Errorf("Expected %v, got %v", expected, result[0])
+		}
+	})
+}
+
+func TestApplyOptionsTransformOrder(t *testing.T) {
+	newOpts := func() Options {
+		return NewOptions().
+			Edit("price", func(value interface{}, row map[string]interface{}) interface{} {
+				return value.(int) * 2
+			}).
+			Add("price_label", func(row map[string]interface{}) interface{} {
+				return fmt.Sprintf("$%v", row["price"])
+			}).
+			Remove("price")
+	}
+
+	t.Run("Default order", func(t *testing.T) {
+		data := []map[string]interface{}{{"price": 5}}
+
+		result := applyOptions(nil, data, newOpts(), 0)
+
+		expected := map[string]interface{}{"price_label": "$5", "DT_RowIndex": 1}
+		if !reflect.DeepEqual(result[0], expected) {
+			t.Errorf("Expected %v, got %v", expected, result[0])
+		}
+	})
+
+	t.Run("Add after Edit sees edited values", func(t *testing.T) {
+		data := []map[string]interface{}{{"price": 5}}
+
+		opts := newOpts().WithTransformOrder([]Stage{StageIndex, StageEdit, StageAdd, StageRemove})
+		result := applyOptions(nil, data, opts, 0)
+
+		expected := map[string]interface{}{"price_label": "$10", "DT_RowIndex": 1}
+		if !reflect.DeepEqual(result[0], expected) {
+			t.Errorf("Expected %v, got %v", expected, result[0])
+		}
+	})
+
+	t.Run("Omitted stages run last", func(t *testing.T) {
+		data := []map[string]interface{}{{"price": 5}}
+
+		// Add runs first and sees no index; Index and Remove run afterwards
+		opts := NewOptions().
+			Add("has_index", func(row map[string]interface{}) interface{} {
+				_, ok := row["DT_RowIndex"]
+				return ok
+			}).
+			Remove("price").
+			WithTransformOrder([]Stage{StageAdd, StageAdd, Stage(42)})
+		result := applyOptions(nil, data, opts, 0)
+
+		expected := map[string]interface{}{"has_index": false, "DT_RowIndex": 1}
+		if !reflect.DeepEqual(result[0], expected) {
+			t.Errorf("Expected %v, got %v", expected, result[0])
+		}
+	})
+}
+
+func TestApplyOptionsHTMLEscape(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1, "name": "<b>John</b>", "bio": `"Tom" & Jerry`}}
+	actions := func(row map[string]interface{}) interface{} { return `<a href="/users/1">Edit</a>` }
+	trusted := func(row map[string]interface{}) interface{} { return template.HTML("<i>ok</i>") }
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected map[string]interface{}
+	}{
+		{
+			"Listed columns are escaped",
+			NewOptions().WithHTMLEscape("name"),
+			map[string]interface{}{"name": "&lt;b&gt;John&lt;/b&gt;", "bio": `"Tom" & Jerry`},
+		},
+		{
+			"All columns are escaped",
+			NewOptions().WithHTMLEscape().Add("actions", actions),
+			map[string]interface{}{"name": "&lt;b&gt;John&lt;/b&gt;", "bio": "&#34;Tom&#34; &amp; Jerry", "actions": `&lt;a href=&#34;/users/1&#34;&gt;Edit&lt;/a&gt;`},
+		},
+		{
+			"Raw columns are left unchanged",
+			NewOptions().WithHTMLEscape().WithRawHTML("actions").Add("actions", actions),
+			map[string]interface{}{"name": "&lt;b&gt;John&lt;/b&gt;", "actions": `<a href="/users/1">Edit</a>`},
+		},
+		{
+			"template.HTML values are trusted",
+			NewOptions().WithHTMLEscape("status").Add("status", trusted),
+			map[string]interface{}{"status": template.HTML("<i>ok</i>")},
+		},
+		{
+			"No escaping by default",
+			NewOptions().Add("actions", actions),
+			map[string]interface{}{"name": "<b>John</b>", "actions": `<a href="/users/1">Edit</a>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := applyOptions(nil, rows, tt.opts, 0)
+
+			for col, expected := range tt.expected {
+				if result[0][col] != expected {
+					t.Errorf("Column %s: expected %v, got %v", col, expected, result[0][col])
+				}
+			}
+			if result[0]["id"] != 1 || result[0]["DT_RowIndex"] != 1 {
+				t.Errorf("Expected non-string values to be unchanged, got %v", result[0])
+			}
+		})
+	}
+
+	if rows[0]["name"] != "<b>John</b>" {
+		t.Errorf("Original rows should not be modified, got %v", rows[0]["name"])
+	}
+}
+
+func TestApplyOptionsDeletedFlag(t *testing.T) {
+	deletedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	rows := []map[string]interface{}{
+		{"id": 1, "deleted_at": nil},
+		{"id": 2, "deleted_at": deletedAt},
+		{"id": 3, "deleted_at": gorm.DeletedAt{}},
+		{"id": 4, "deleted_at": gorm.DeletedAt{Time: deletedAt, Valid: true}},
+		{"id": 5, "deleted_at": (*time.Time)(nil)},
+		{"id": 6, "deleted_at": &deletedAt},
+		{"id": 7},
+	}
+	expected := []bool{false, true, false, true, false, true, false}
+
+	opts := NewOptions().WithDeletedFlag("deleted_at", "is_deleted").Remove("deleted_at")
+	result := applyOptions(nil, rows, opts, 0)
+
+	for i, row := range result {
+		if row["is_deleted"] != expected[i] {
+			t.Errorf("Row %v: expected is_deleted=%v, got %v", row["id"], expected[i], row["is_deleted"])
+		}
+		if _, exists := row["deleted_at"]; exists {
+			t.Errorf("Row %v: expected deleted_at to be removed", row["id"])
+		}
+	}
+
+	if _, exists := applyOptions(nil, rows, NewOptions(), 0)[0]["is_deleted"]; exists {
+		t.Error("Expected no flag without WithDeletedFlag")
+	}
+}
+
+func TestApplyOptionsValueMapper(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1, "name": "John", "tax_ssn": "123-45-6789", "score": 3.14159}}
+	round := func(column string, value interface{}) interface{} {
+		if f, ok := value.(float64); ok {
+			return math.Round(f*100) / 100
+		}
+		if strings.HasSuffix(column, "_ssn") {
+			return "***"
+		}
+		return value
+	}
+
+	opts := NewOptions().
+		WithValueMapper(round).
+		Add("rating", func(row map[string]interface{}) interface{} { return 4.5678 }).
+		Edit("name", func(value interface{}, row map[string]interface{}) interface{} { return "Mr. " + value.(string) }).
+		Remove("score")
+
+	result := applyOptions(nil, rows, opts, 0)[0]
+
+	expected := map[string]interface{}{"id": 1, "name": "Mr. John", "tax_ssn": "***", "rating": 4.57, "DT_RowIndex": 1}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	t.Run("Identity mapper is a no-op", func(t *testing.T) {
+		identity := func(column string, value interface{}) interface{} { return value }
+		result := applyOptions(nil, rows, NewOptions().WithValueMapper(identity), 0)[0]
+
+		if !reflect.DeepEqual(result, applyOptions(nil, rows, NewOptions(), 0)[0]) {
+			t.Errorf("Expected unchanged values, got %v", result)
+		}
+	})
+
+	t.Run("Internal keys are not mapped", func(t *testing.T) {
+		seen := map[string]bool{}
+		record := func(column string, value interface{}) interface{} {
+			seen[column] = true
+			return value
+		}
+		applyOptions(nil, rows, NewOptions().WithValueMapper(record).WithRowId("id"), 0)
+
+		if seen["DT_RowIndex"] || seen["DT_RowId"] || !seen["name"] {
+			t.Errorf("Expected only data columns to be mapped, got %v", seen)
+		}
+	})
+}
+
+func TestTransformOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    []Stage
+		expected []Stage
+	}{
+		{"Empty is the default", nil, defaultTransformOrder},
+		{"Full custom order", []Stage{StageRemove, StageAlias, StageTrim, StageEdit, StageAdd, StageIndex},
+			[]Stage{StageRemove, StageAlias, StageTrim, StageEdit, StageAdd, StageIndex}},
+		{"Missing, duplicate and unknown stages", []Stage{StageEdit, StageEdit, Stage(-1), StageAdd},
+			[]Stage{StageEdit, StageAdd, StageIndex, StageTrim, StageAlias, StageRemove}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if order := transformOrder(tt.order); !reflect.DeepEqual(order, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, order)
+			}
+		})
+	}
+}
+
+func TestApplyOptionsOrthogonal(t *testing.T) {
+	data := []map[string]interface{}{
+		{"price": 1250, "name": "Widget"},
+	}
+
+	opts := NewOptions().
+		Edit("price", func(value interface{}, row map[string]interface{}) interface{} {
+			return value.(int) * 2
+		}).
+		WithOrthogonal("price",
+			func(value interface{}, row map[string]interface{}) interface{} {
+				return fmt.Sprintf("$%d.%02d", value.(int)/100, value.(int)%100)
+			},
+			nil,
+		).
+		WithOrthogonal("name", nil, func(value interface{}, row map[string]interface{}) interface{} {
+			return strings.ToLower(value.(string))
+		}).
+		WithOrthogonal("missing", nil, nil)
+	result := applyOptions(nil, data, opts, 0)
+
+	expected := map[string]interface{}{
+		"price":       map[string]interface{}{"display": "$25.00", "sort": 2500},
+		"name":        map[string]interface{}{"display": "Widget", "sort": "widget"},
+		"DT_RowIndex": 1,
+	}
+	if !reflect.DeepEqual(result[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, result[0])
+	}
+
+	encoded, err := json.Marshal(result[0]["price"])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(encoded) != `{"display":"$25.00","sort":2500}` {
+		t.Errorf("Unexpected cell JSON: %s", encoded)
+	}
+}
+
+func TestOfReturnColumnACL(t *testing.T) {
+	acl := func(c *gin.Context, column string) bool {
+		if c.GetString("role") == "admin" {
+			return true
+		}
+		return column != "email" && column != "notes"
+	}
+
+	tests := []struct {
+		role     string
+		expected []string
+	}{
+		{"admin", []string{"DT_RowIndex", "email", "id", "name", "notes"}},
+		{"viewer", []string{"DT_RowIndex", "id", "name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			fake := &fakeDB{count: 2, result: userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"}, TestUser{ID: 2, Name: "Jane"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("")
+			c.Set("role", tt.role)
+
+			calls := 0
+			opts := NewOptions().
+				Add("notes", func(row map[string]interface{}) interface{} { return "vip" }).
+				WithColumnACL(func(c *gin.Context, column string) bool {
+					calls++
+					return acl(c, column)
+				})
+
+			var users []TestUser
+			result, err := OfReturn(c, db.Model(&TestUser{}), &users, nil, nil, opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for _, row := range result.Data.([]map[string]interface{}) {
+				keys := make([]string, 0, len(row))
+				for key := range row {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				if !reflect.DeepEqual(keys, tt.expected) {
+					t.Errorf("Expected keys %v, got %v", tt.expected, keys)
+				}
+			}
+			if calls != 4 {
+				t.Errorf("Expected one ACL call per column, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestOfReturnColumnACLSearchAndOrder(t *testing.T) {
+	acl := func(c *gin.Context, column string) bool {
+		return column != "email" || c.GetString("role") == "admin"
+	}
+	searchable := []string{"name", "users.email"}
+	orderable := map[string]string{"name": "name", "contact": "users.email"}
+
+	tests := []struct {
+		role          string
+		expectedWhere string
+		expectedOrder string
+	}{
+		{"admin", "WHERE LOWER(name) LIKE LOWER(?) OR LOWER(users.email) LIKE LOWER(?)", "ORDER BY users.email desc"},
+		{"viewer", "WHERE LOWER(name) LIKE LOWER(?)", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John"})}
+			db := newFakeGormDB(t, fake)
+			c, _ := newTestContext("search[value]=example&order[0][column]=contact&order[0][dir]=desc")
+			c.Set("role", tt.role)
+
+			var users []TestUser
+			if _, err := OfReturn(c, db.Model(&TestUser{}), &users, searchable, orderable, NewOptions().WithColumnACL(acl)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			last := fake.LastSelect()
+			if !strings.Contains(last.SQL, tt.expectedWhere) {
+				t.Errorf("Expected %s, got %s", tt.expectedWhere, last.SQL)
+			}
+			if tt.expectedOrder != "" && !strings.Contains(last.SQL, tt.expectedOrder) {
+				t.Errorf("Expected %s, got %s", tt.expectedOrder, last.SQL)
+			}
+			if tt.expectedOrder == "" && (strings.Contains(last.SQL, "ORDER BY") || strings.Contains(last.SQL, "email")) {
+				t.Errorf("Expected the hidden column to be neither searched nor ordered by, got %s", last.SQL)
+			}
+		})
+	}
+
+	t.Run("OfParams passes an empty context", func(t *testing.T) {
+		fake := &fakeDB{count: 1, result: userResult(TestUser{ID: 1, Name: "John", Email: "john@example.com"})}
+		db := newFakeGormDB(t, fake)
+
+		params := dto.NewParams().WithSearch("example").Build()
+		var users []TestUser
+		result, err := OfParams(db.Model(&TestUser{}), &users, params, searchable, orderable, NewOptions().WithColumnACL(acl))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, ok := result.Data.([]map[string]interface{})[0]["email"]; ok {
+			t.Errorf("Expected email to be hidden without a request, got %v", result.Data)
+		}
+		if strings.Contains(fake.LastSelect().SQL, "email") {
+			t.Errorf("Expected email not to be searched, got %s", fake.LastSelect().SQL)
+		}
+	})
+}
+
+func TestApplyOptionsOmitEmpty(t *testing.T) {
+	var nilName *string
+	rows := []map[string]interface{}{{
+		"id":         0,
+		"name":       "John",
+		"email":      "",
+		"active":     false,
+		"admin":      true,
+		"score":      0.0,
+		"rank":       int64(3),
+		"manager":    nil,
+		"nickname":   nilName,
+		"created_at": time.Time{},
+		"tags":       []string{},
+	}}
+
+	t.Run("Zero values are dropped", func(t *testing.T) {
+		opts := NewOptions().WithOmitEmpty(true).WithRowId("id").WithIndexBase(0).
+			Add("badge", func(row map[string]interface{}) interface{} { return "" })
+		result := applyOptions(nil, rows, opts, 0)[0]
+
+		expected := map[string]interface{}{
+			"name":        "John",
+			"admin":       true,
+			"rank":        int64(3),
+			"tags":        []string{},
+			"DT_RowIndex": 0,
+			"DT_RowId":    "0",
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		result := applyOptions(nil, rows, NewOptions(), 0)[0]
+		if len(result) != len(rows[0])+1 {
+			t.Errorf("Expected every column to be kept, got %v", result)
+		}
+	})
+}
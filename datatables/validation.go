@@ -1,6 +1,13 @@
 package datatables
 
-import "regexp"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 // columnNamePattern defines the allowed pattern for column names
 // Allows: alphanumeric characters, underscores, and dots (for table.column notation)
@@ -23,16 +30,80 @@ func isValidColumnName(name string) bool {
 	return columnNamePattern.MatchString(name)
 }
 
+// IsValidColumnName reports whether a column name matches the default
+// validation pattern (letters, digits, underscores, and dots). It is useful
+// as a building block for WithSearchableValidator and WithOrderableValidator.
+func IsValidColumnName(name string) bool {
+	return isValidColumnName(name)
+}
+
+// invalidColumnDetail explains why a column name is invalid, naming the first
+// offending character and its position so logs show exactly what to fix.
+// The result is appended to ValidationError messages.
+//
+// Returns an empty string if the column name is valid.
+func invalidColumnDetail(name string) string {
+	if name == "" {
+		return ": name is empty"
+	}
+
+	for i, r := range []rune(name) {
+		if r == '_' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			continue
+		}
+		return fmt.Sprintf(": %s at position %d is not allowed (allowed: letters, digits, underscores, and dots)", describeRune(r), i)
+	}
+
+	return ""
+}
+
+// validColumn checks a column name with the custom validator,
+// or with isValidColumnName when valid is nil.
+func validColumn(name string, valid func(name string) bool) bool {
+	if valid == nil {
+		return isValidColumnName(name)
+	}
+	return valid(name)
+}
+
+// columnDetail explains why a column name was rejected. Names that pass the
+// default pattern were rejected by the custom validator.
+func columnDetail(name string, valid func(name string) bool) string {
+	if detail := invalidColumnDetail(name); detail != "" || valid == nil {
+		return detail
+	}
+	return ": rejected by the custom column validator"
+}
+
+// describeRune quotes a character for an error message, naming invisible ones.
+func describeRune(r rune) string {
+	switch r {
+	case ' ':
+		return "space"
+	case '\t':
+		return "tab"
+	case '\n':
+		return "newline"
+	}
+	return fmt.Sprintf("character %q", r)
+}
+
 // validateSearchableColumns validates all searchable column names
 // to ensure they are safe for SQL queries.
 //
 // Returns an error if any column name is invalid.
 func validateSearchableColumns(columns []string) error {
+	return validateSearchableColumnsWith(columns, nil)
+}
+
+// validateSearchableColumnsWith validates searchable column names with a
+// custom validator, falling back to isValidColumnName when valid is nil.
+func validateSearchableColumnsWith(columns []string, valid func(name string) bool) error {
 	for _, col := range columns {
-		if !isValidColumnName(col) {
+		if !validColumn(col, valid) {
 			return &ValidationError{
 				Field:   col,
-				Message: "searchable column name contains invalid characters",
+				Message: "searchable column name contains invalid characters" + columnDetail(col, valid),
 			}
 		}
 	}
@@ -44,17 +115,210 @@ func validateSearchableColumns(columns []string) error {
 //
 // Returns an error if any column name is invalid.
 func validateOrderableColumns(columns map[string]string) error {
+	return validateOrderableColumnsWith(columns, nil)
+}
+
+// validateOrderableColumnsWith validates orderable column mappings with a
+// custom validator, falling back to isValidColumnName when valid is nil.
+func validateOrderableColumnsWith(columns map[string]string, valid func(name string) bool) error {
 	for key, val := range columns {
-		if !isValidColumnName(key) {
+		if !validColumn(key, valid) {
 			return &ValidationError{
 				Field:   key,
-				Message: "orderable column key contains invalid characters",
+				Message: "orderable column key contains invalid characters" + columnDetail(key, valid),
 			}
 		}
-		if !isValidColumnName(val) {
+		if !validColumn(val, valid) {
 			return &ValidationError{
 				Field:   val,
-				Message: "orderable column value contains invalid characters",
+				Message: "orderable column value contains invalid characters" + columnDetail(val, valid),
+			}
+		}
+	}
+	return nil
+}
+
+// validateSearchableMapWith validates all searchable column mappings to
+// ensure both keys and values are safe for SQL queries, using a custom
+// validator or isValidColumnName when valid is nil.
+//
+// Returns an error if any column name is invalid.
+func validateSearchableMapWith(columns map[string]string, valid func(name string) bool) error {
+	for key, val := range columns {
+		if !validColumn(key, valid) {
+			return &ValidationError{
+				Field:   key,
+				Message: "searchable column key contains invalid characters" + columnDetail(key, valid),
+			}
+		}
+		if !validColumn(val, valid) {
+			return &ValidationError{
+				Field:   val,
+				Message: "searchable column value contains invalid characters" + columnDetail(val, valid),
+			}
+		}
+	}
+	return nil
+}
+
+// validateSearchNormalizers validates the column names of all search normalizers.
+// Replacement strings are passed as query parameters and need no validation.
+//
+// Returns an error if any column name is invalid.
+func validateSearchNormalizers(normalizers map[string]map[string]string) error {
+	for col, replacements := range normalizers {
+		if !isValidColumnName(col) {
+			return &ValidationError{
+				Field:   col,
+				Message: "search normalizer column name contains invalid characters" + invalidColumnDetail(col),
+			}
+		}
+		for from := range replacements {
+			if from == "" {
+				return &ValidationError{
+					Field:   col,
+					Message: "search normalizer replacement must not be empty",
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateComputedColumns validates the aliases of all computed columns.
+// The SQL expressions are developer-trusted and are not validated.
+//
+// Returns an error if any alias is invalid or an expression is empty.
+func validateComputedColumns(columns []ComputedColumn) error {
+	for _, col := range columns {
+		if !isValidColumnName(col.Alias) {
+			return &ValidationError{
+				Field:   col.Alias,
+				Message: "computed column alias contains invalid characters" + invalidColumnDetail(col.Alias),
+			}
+		}
+		if strings.TrimSpace(col.Expr) == "" {
+			return &ValidationError{
+				Field:   col.Alias,
+				Message: "computed column expression must not be empty",
+			}
+		}
+	}
+	return nil
+}
+
+// validateFooterAggregates validates the output keys of all footer aggregates.
+// The SQL expressions are developer-trusted and are not validated.
+//
+// Returns an error if any key is invalid or an expression is empty.
+func validateFooterAggregates(aggregates []FooterAggregate) error {
+	for _, agg := range aggregates {
+		if !isValidColumnName(agg.Key) {
+			return &ValidationError{
+				Field:   agg.Key,
+				Message: "footer aggregate key contains invalid characters" + invalidColumnDetail(agg.Key),
+			}
+		}
+		if strings.TrimSpace(agg.Expr) == "" {
+			return &ValidationError{
+				Field:   agg.Key,
+				Message: "footer aggregate expression must not be empty",
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the options for inconsistent configurations, so mistakes
+// surface at startup rather than at request time:
+//   - a column both added (Add, AddWithValues) and removed
+//   - a column both edited (Edit, EditWithValues) and removed
+//   - ResetIndex set without an IndexColumn
+//   - a malformed DefaultOrder (each term must be "column [ASC|DESC]")
+//   - a negative IndexBase (see WithIndexBase)
+//
+// Returns nil if the options are consistent, otherwise an error joining a
+// ValidationError for every problem found (see errors.Join).
+//
+// Example:
+//   opts := datatables.NewOptions().Add("badge", badgeFn).Remove("badge")
+//   if err := opts.Validate(); err != nil {
+//       log.Fatal(err)
+//   }
+func (o Options) Validate() error {
+	var errs []error
+
+	removed := append(globalRemoveColumns(), o.RemoveColumns...)
+
+	added := make([]string, 0, len(o.AddColumns)+len(o.AddValueColumns))
+	for col := range o.AddColumns {
+		added = append(added, col)
+	}
+	for col := range o.AddValueColumns {
+		added = append(added, col)
+	}
+	sort.Strings(added)
+	for _, col := range added {
+		if containsString(removed, col) {
+			errs = append(errs, &ValidationError{Field: col, Message: "column is both added and removed"})
+		}
+	}
+
+	edited := make([]string, 0, len(o.EditColumns)+len(o.EditValueColumns))
+	for col := range o.EditColumns {
+		edited = append(edited, col)
+	}
+	for col := range o.EditValueColumns {
+		edited = append(edited, col)
+	}
+	sort.Strings(edited)
+	for _, col := range edited {
+		if containsString(removed, col) {
+			errs = append(errs, &ValidationError{Field: col, Message: "column is edited but also removed"})
+		}
+	}
+
+	if o.ResetIndex && o.IndexColumn == "" {
+		errs = append(errs, &ValidationError{Field: "IndexColumn", Message: "ResetIndex is set but IndexColumn is empty"})
+	}
+
+	if o.DefaultOrder != "" {
+		if err := validateDefaultOrder(o.DefaultOrder); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := validateIndexBase(o); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateIndexBase checks that the index base set with WithIndexBase is non-negative.
+func validateIndexBase(o Options) error {
+	if base := o.indexBase(); base < 0 {
+		return &ValidationError{Field: "IndexBase", Message: "index base must be non-negative, got " + strconv.Itoa(base)}
+	}
+	return nil
+}
+
+// validateDefaultOrder checks that every term of an ORDER BY clause is a
+// valid column name optionally followed by ASC or DESC.
+func validateDefaultOrder(order string) error {
+	for _, term := range strings.Split(order, ",") {
+		fields := strings.Fields(term)
+
+		valid := len(fields) >= 1 && len(fields) <= 2 && isValidColumnName(fields[0])
+		if valid && len(fields) == 2 {
+			dir := strings.ToUpper(fields[1])
+			valid = dir == "ASC" || dir == "DESC"
+		}
+
+		if !valid {
+			return &ValidationError{
+				Field:   "DefaultOrder",
+				Message: "malformed term \"" + strings.TrimSpace(term) + "\": expected \"column [ASC|DESC]\"",
 			}
 		}
 	}
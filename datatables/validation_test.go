@@ -1,6 +1,8 @@
 package datatables
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -116,3 +118,140 @@ func TestValidateOrderableColumns(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationErrorDetail(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   string
+		contains string
+	}{
+		{"Space", "user name", "space at position 4 is not allowed"},
+		{"Quote", "id'", `character '\'' at position 2 is not allowed`},
+		{"Semicolon", "id; DROP TABLE users", "character ';' at position 2"},
+		{"Empty", "", "name is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSearchableColumns([]string{tt.column})
+			if err == nil {
+				t.Fatalf("Expected error for %q", tt.column)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error to mention %q, got %q", tt.contains, err.Error())
+			}
+			if !strings.Contains(err.Error(), "allowed") && tt.column != "" {
+				t.Errorf("Expected error to describe the allowed pattern, got %q", err.Error())
+			}
+		})
+	}
+
+	if detail := invalidColumnDetail("users.name_2"); detail != "" {
+		t.Errorf("Expected no detail for a valid name, got %q", detail)
+	}
+}
+
+func TestCustomColumnValidators(t *testing.T) {
+	noDots := func(name string) bool {
+		return IsValidColumnName(name) && !strings.Contains(name, ".")
+	}
+	allowDashes := func(name string) bool {
+		return IsValidColumnName(strings.ReplaceAll(name, "-", "_"))
+	}
+
+	opts := NewOptions().WithSearchableValidator(noDots).WithOrderableValidator(allowDashes)
+	db := newFakeGormDB(t, &fakeDB{})
+
+	tests := []struct {
+		name       string
+		searchable []string
+		orderable  map[string]string
+		shouldErr  bool
+	}{
+		{"Plain columns pass both", []string{"name"}, map[string]string{"name": "name"}, false},
+		{"Dots rejected for searchable", []string{"users.name"}, nil, true},
+		{"Dots still allowed for orderable", nil, map[string]string{"name": "users.name"}, false},
+		{"Dashes allowed for orderable", nil, map[string]string{"full-name": "full_name"}, false},
+		{"Dashes still rejected for searchable", []string{"full-name"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveColumns(db, &TestUser{}, tt.searchable, tt.orderable, opts)
+			if (err != nil) != tt.shouldErr {
+				t.Errorf("resolveColumns() error = %v, shouldErr %v", err, tt.shouldErr)
+			}
+		})
+	}
+
+	t.Run("Message names the custom validator", func(t *testing.T) {
+		_, err := resolveColumns(db, &TestUser{}, []string{"users.name"}, nil, opts)
+		if err == nil || !strings.Contains(err.Error(), "custom column validator") {
+			t.Errorf("Expected custom validator message, got %v", err)
+		}
+	})
+
+	t.Run("Defaults apply without validators", func(t *testing.T) {
+		if _, err := resolveColumns(db, &TestUser{}, []string{"users.name"}, nil, NewOptions()); err != nil {
+			t.Errorf("Expected default validation to allow dots, got %v", err)
+		}
+	})
+}
+
+func TestOptionsValidate(t *testing.T) {
+	badge := func(row map[string]interface{}) interface{} { return "badge" }
+	upper := func(value interface{}, row map[string]interface{}) interface{} { return value }
+
+	tests := []struct {
+		name     string
+		opts     Options
+		problems []string
+	}{
+		{"Default options", NewOptions(), nil},
+		{"Valid configuration", NewOptions().Add("badge", badge).Edit("name", upper).Remove("password").WithDefaultOrder("created_at DESC, id"), nil},
+		{"Add and Remove the same column", NewOptions().Add("badge", badge).Remove("badge"), []string{"'badge': column is both added and removed"}},
+		{"Edit a removed column", NewOptions().Edit("name", upper).Remove("name"), []string{"'name': column is edited but also removed"}},
+		{"ResetIndex without IndexColumn", NewOptions().WithIndex("", true), []string{"ResetIndex is set but IndexColumn is empty"}},
+		{"Malformed DefaultOrder direction", NewOptions().WithDefaultOrder("created_at DOWN"), []string{`malformed term "created_at DOWN"`}},
+		{"Malformed DefaultOrder column", NewOptions().WithDefaultOrder("id, name; DROP TABLE users"), []string{`malformed term "name; DROP TABLE users"`}},
+		{"Empty DefaultOrder term", NewOptions().WithDefaultOrder("id,"), []string{`malformed term ""`}},
+		{"Zero IndexBase", NewOptions().WithIndexBase(0), nil},
+		{"Negative IndexBase", NewOptions().WithIndexBase(-1), []string{"index base must be non-negative, got -1"}},
+		{
+			"Multiple problems",
+			NewOptions().Add("badge", badge).Edit("name", upper).Remove("badge", "name").WithIndex("", true),
+			[]string{"both added and removed", "edited but also removed", "IndexColumn is empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected problems %v, got nil", tt.problems)
+			}
+
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.problems) {
+				t.Errorf("Expected %d problems, got %d: %v", len(tt.problems), len(lines), err)
+			}
+			for _, problem := range tt.problems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("Expected error to mention %q, got %v", problem, err)
+				}
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %T", err)
+			}
+		})
+	}
+}